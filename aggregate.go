@@ -0,0 +1,52 @@
+package gdct
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// ErrAggregateNotAllowed is returned by Aggregate for a function missing from the aggregate whitelist.
+var ErrAggregateNotAllowed = fmt.Errorf("aggregate function not allowed")
+
+var (
+	aggregateNameRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+	aggregatesMu sync.RWMutex
+	aggregates   = map[string]bool{
+		"COUNT": true,
+		"SUM":   true,
+		"AVG":   true,
+		"MIN":   true,
+		"MAX":   true,
+	}
+)
+
+/*
+RegisterAggregate
+
+@ names: Aggregate function names accepted by Aggregate from now on, e.g. "STDDEV" or "BIT_OR"
+@ Return: Error if a name is not a plain function name
+*/
+func RegisterAggregate(names ...string) error {
+	for _, name := range names {
+		if !aggregateNameRegexp.MatchString(name) {
+			return fmt.Errorf("invalid aggregate function name %q", name)
+		}
+	}
+
+	aggregatesMu.Lock()
+	defer aggregatesMu.Unlock()
+	for _, name := range names {
+		aggregates[strings.ToUpper(name)] = true
+	}
+	return nil
+}
+
+// isAllowedAggregate reports whether function is on the aggregate whitelist, ignoring case.
+func isAllowedAggregate(function string) bool {
+	aggregatesMu.RLock()
+	defer aggregatesMu.RUnlock()
+	return aggregates[strings.ToUpper(function)]
+}
@@ -0,0 +1,59 @@
+package gdct
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAggregateWhitelist(t *testing.T) {
+	tests := []struct {
+		name          string
+		function      string
+		column        string
+		expectedQuery string
+		expectedErr   error
+	}{
+		{"count star", "COUNT", "*", "SELECT user_id, COUNT(*) FROM orders GROUP BY user_id", nil},
+		{"lowercase sum", "sum", "amount", "SELECT user_id, sum(amount) FROM orders GROUP BY user_id", nil},
+		{"injection", "DROP TABLE users; --", "x", "", ErrAggregateNotAllowed},
+		{"unknown function", "pg_sleep", "amount", "", ErrAggregateNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, _, err := BuildSelect(PostgreSQL, "orders", "user_id").Aggregate(tt.function, tt.column).GroupBy("user_id").Build()
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+			if query != tt.expectedQuery {
+				t.Errorf("Expected %q, got %q", tt.expectedQuery, query)
+			}
+		})
+	}
+}
+
+func TestRegisterAggregate(t *testing.T) {
+	if err := RegisterAggregate("stddev"); err != nil {
+		t.Fatalf("RegisterAggregate failed: %v", err)
+	}
+	defer func() {
+		aggregatesMu.Lock()
+		delete(aggregates, "STDDEV")
+		aggregatesMu.Unlock()
+	}()
+
+	query, _, err := BuildSelect(PostgreSQL, "orders", "user_id").Aggregate("STDDEV", "amount").GroupBy("user_id").Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := "SELECT user_id, STDDEV(amount) FROM orders GROUP BY user_id"
+	if query != expected {
+		t.Errorf("Expected %q, got %q", expected, query)
+	}
+
+	for _, name := range []string{"", "*", "x) --", "schema.fn"} {
+		if err := RegisterAggregate(name); err == nil {
+			t.Errorf("Expected error registering %q", name)
+		}
+	}
+}
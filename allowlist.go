@@ -0,0 +1,89 @@
+package gdct
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ErrColumnNotAllowed is returned for columns missing from the AllowColumns list.
+var ErrColumnNotAllowed = fmt.Errorf("column not allowed")
+
+// conditionColumnRegexp matches a Where condition comparing a single column with placeholders,
+// such as "age >= ?", "role IN (?, ?)" or "deleted_at IS NULL", and captures the column.
+var conditionColumnRegexp = regexp.MustCompile(`(?i)^\s*([A-Za-z_][A-Za-z0-9_$.]*)\s*(?:` +
+	`(?:=|<>|!=|<=|>=|<|>|(?:NOT\s+)?I?LIKE)\s*\?` +
+	`|(?:NOT\s+)?IN\s*\(\s*\?(?:\s*,\s*\?)*\s*\)` +
+	`|(?:NOT\s+)?BETWEEN\s+\?\s+AND\s+\?` +
+	`|IS\s+(?:NOT\s+)?(?:NULL|\?))\s*$`)
+
+/*
+AllowColumns
+
+@ cols: Columns the builder accepts, qualified ("u.name") or bare ("name")
+@ Return: *QueryBuilder validating later column names against the list
+
+Once set, the constructor and Select columns, GroupBy, OrderBy, OrderByStrict, OrderByMulti,
+the column-based Where helpers and the compared column of Where and OrWhere conditions
+fail with ErrColumnNotAllowed for columns missing from the list.
+A qualified column is also accepted when its bare name is listed.
+Where and OrWhere conditions other than a single column compared with placeholders ("age >= ?")
+are rejected, since their columns cannot be checked; write them with WhereRaw.
+WhereRaw, SelectRaw and Having are not checked.
+*/
+func (qb *QueryBuilder) AllowColumns(cols ...string) *QueryBuilder {
+	if qb.err != nil {
+		return qb
+	}
+	allowed := make(map[string]bool, len(qb.allowed)+len(cols))
+	for col := range qb.allowed {
+		allowed[col] = true
+	}
+	for _, col := range cols {
+		if err := ValidateIdentifier(col); err != nil {
+			qb.err = err
+			return qb
+		}
+		allowed[col] = true
+	}
+	qb.allowed = allowed
+	qb.checkColumns(qb.selected...)
+	return qb
+}
+
+// checkColumns records ErrColumnNotAllowed for the first column missing from the allow-list.
+// Select-list aliases ("name AS n", "name n") are ignored.
+func (qb *QueryBuilder) checkColumns(columns ...string) bool {
+	if qb.allowed == nil || qb.err != nil {
+		return qb.err == nil
+	}
+	for _, col := range columns {
+		name := col
+		if fields := strings.Fields(col); len(fields) > 0 {
+			name = fields[0]
+		}
+		if qb.allowed[name] {
+			continue
+		}
+		if i := strings.LastIndexByte(name, '.'); i >= 0 && qb.allowed[name[i+1:]] {
+			continue
+		}
+		qb.err = fmt.Errorf("%w: %q", ErrColumnNotAllowed, name)
+		return false
+	}
+	return true
+}
+
+// checkConditionColumn validates the compared column of a Where condition.
+// Conditions the allow-list cannot parse are rejected.
+func (qb *QueryBuilder) checkConditionColumn(condition string) bool {
+	if qb.allowed == nil || qb.err != nil {
+		return qb.err == nil
+	}
+	match := conditionColumnRegexp.FindStringSubmatch(condition)
+	if match == nil {
+		qb.err = fmt.Errorf("%w: cannot check the columns of condition %q, use WhereRaw", ErrColumnNotAllowed, condition)
+		return false
+	}
+	return qb.checkColumns(match[1])
+}
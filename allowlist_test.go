@@ -0,0 +1,73 @@
+package gdct
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAllowColumns(t *testing.T) {
+	allowed := func() *QueryBuilder {
+		return BuildSelect(PostgreSQL, "users", "id").AllowColumns("id", "name", "age", "status")
+	}
+
+	query, _, err := allowed().
+		Select("u.name").
+		Where("age >= ?", 18).
+		WhereIn("status", []interface{}{"active"}).
+		GroupBy("status").
+		OrderBy("name", "ASC", nil).
+		Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := "SELECT id, u.name FROM users WHERE age >= $1 AND status IN ($2) GROUP BY status ORDER BY name ASC"
+	if query != expected {
+		t.Errorf("Expected %q, got %q", expected, query)
+	}
+
+	rejected := []struct {
+		name string
+		qb   *QueryBuilder
+	}{
+		{"Select", allowed().Select("password")},
+		{"Where", allowed().Where("password = ?", "x")},
+		{"Where NOT LIKE", allowed().Where("email NOT LIKE ?", "%x")},
+		{"WhereIn", allowed().WhereIn("role", []interface{}{"admin"})},
+		{"WhereBetween", allowed().WhereBetween("salary", 1, 2)},
+		{"GroupBy", allowed().GroupBy("role")},
+		{"OrderBy", allowed().OrderBy("password", "ASC", nil)},
+		{"OrderByMulti", allowed().OrderByMulti(OrderSpec{Column: "created_at"})},
+		{"AfterCursor", allowed().AfterCursor("created_at", 10, "ASC")},
+		{"OrWhere", allowed().Where("age > ?", 1).OrWhere("password = ?", "x")},
+		{"Where function", allowed().Where("LOWER(password) = ?", "x")},
+		{"Where parenthesized", allowed().Where("(password = ?)", "x")},
+		{"Where tautology", allowed().Where("1=1 OR password = ?", "x")},
+		{"Where trailing OR", allowed().Where("age = ? OR password = ?", 1, "x")},
+		{"Constructor", BuildSelect(PostgreSQL, "users", "id", "password").AllowColumns("id", "name")},
+		{"Select before AllowColumns", BuildSelect(PostgreSQL, "users").Select("password").AllowColumns("id")},
+	}
+
+	for _, tt := range rejected {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := tt.qb.Build(); !errors.Is(err, ErrColumnNotAllowed) {
+				t.Errorf("Expected ErrColumnNotAllowed, got %v", err)
+			}
+		})
+	}
+
+	accepted := []string{"age IN (?, ?)", "status NOT IN (?)", "age BETWEEN ? AND ?", "name IS NULL", "u.name NOT LIKE ?"}
+	for _, condition := range accepted {
+		if _, _, err := allowed().Where(condition, 1, 2).Build(); err != nil {
+			t.Errorf("Expected %q to pass the allow-list, got %v", condition, err)
+		}
+	}
+
+	if _, _, err := allowed().AfterCursor("id", 10, "ASC").Build(); err != nil {
+		t.Errorf("Expected AfterCursor on an allowed column to pass, got %v", err)
+	}
+
+	// WhereRaw is the explicit escape hatch for expressions the allow-list cannot parse
+	if _, _, err := allowed().WhereRaw("password IS NULL").Build(); err != nil {
+		t.Errorf("Expected WhereRaw to skip the allow-list, got %v", err)
+	}
+}
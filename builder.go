@@ -1,10 +1,16 @@
 package gdct
 
 import (
+	"encoding/json"
 	"fmt"
+	"reflect"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
+
+	"github.com/lib/pq"
 )
 
 // DBType represents the type of database.
@@ -15,6 +21,7 @@ const (
 	MariaDB    DBType = "mariadb"
 	Mysql      DBType = "mysql"
 	Sqlite     DBType = "sqlite3"
+	SQLServer  DBType = "sqlserver" // Query building only, no connection support
 )
 
 // String returns the string representation of DBType.
@@ -22,52 +29,95 @@ func (d DBType) String() string {
 	return string(d)
 }
 
-// IsValid checks if the DBType is valid.
+// IsValid checks if the DBType has a registered dialect.
 func (d DBType) IsValid() bool {
-	switch d {
-	case PostgreSQL, MariaDB, Mysql, Sqlite:
-		return true
-	default:
-		return false
-	}
+	_, ok := lookupDialect(d)
+	return ok
 }
 
 // QueryBuilder is a flexible SQL query builder.
 type QueryBuilder struct {
 	op         string                 // "SELECT", "INSERT", "UPDATE", "DELETE"
 	dbType     DBType                 // Database type for dialect-specific handling
+	dialect    Dialect                // Dialect resolved from dbType
 	table      string                 // Table name
 	columns    []string               // SELECT columns
-	joins      []string               // JOIN clauses
-	conditions []string               // WHERE conditions
+	joins      []sqlClause            // JOIN clauses
+	conditions []sqlClause            // WHERE conditions
 	groupBy    []string               // GROUP BY columns
-	having     []string               // HAVING conditions
+	having     []sqlClause            // HAVING conditions
 	orderBy    string                 // ORDER BY clause
-	limit      int                    // LIMIT value
-	offset     int                    // OFFSET value
-	args       []interface{}          // Query arguments
+	limit      int64                  // LIMIT value
+	offset     int64                  // OFFSET value
+	top        int64                  // TOP value (databases supporting FeatureTop)
+	args       []interface{}          // Arguments of Subquery fragments in the SELECT list
 	distinct   bool                   // DISTINCT flag
 	err        error                  // Error accumulator
 	data       map[string]interface{} // Data for INSERT and UPDATE
-	returning  string                 // RETURNING clause (PostgreSQL only)
+	rowColumns []string               // Columns of a multi-row INSERT
+	rows       [][]interface{}        // Rows of a multi-row INSERT
+	sets       []sqlClause            // Additional UPDATE assignments
+	returning  string                 // RETURNING clause (databases supporting FeatureReturning)
+	conflict   *conflictClause        // ON CONFLICT clause for upserts
+	from       *sqlClause             // Derived table replacing table in FROM
+	ctes       []sqlClause            // WITH common table expressions
+	recursive  bool                   // WITH RECURSIVE flag
+	ignore     bool                   // Skip rows conflicting with existing ones on INSERT
+	replace    bool                   // REPLACE INTO instead of INSERT INTO
+	explain    string                 // EXPLAIN prefix added by Build
+	defaults   bool                   // INSERT a row of column defaults when no data is given
+	allowed    map[string]bool        // Column allow-list set by AllowColumns
+	selected   []string               // Constructor and Select columns, checked when AllowColumns is called
+	skipZero   bool                   // AddWhereIfNotEmpty skips zero numbers
+
+	softDeleteColumn string // Soft-delete timestamp column
+	withTrashed      bool   // Include soft-deleted rows
+
+	lockColumn  string // Optimistic lock version column
+	lockVersion int    // Expected current version for optimistic locking
+
+	orderFallback string // Column OrderBy uses for disallowed columns instead of "id"
+}
+
+// sqlClause is a SQL fragment using "?" placeholders together with its arguments.
+// Placeholders are rendered for the dialect when the query is built.
+type sqlClause struct {
+	sql  string
+	args []interface{}
 }
 
+// Raw is a SQL expression written literally into INSERT values, UPDATE assignments
+// and SelectCoalesce/SelectNullIf arguments instead of being bound.
+// Never build a Raw from user input.
+type Raw string
+
+// Now is the current date and time as evaluated by the database.
+// CURRENT_TIMESTAMP is understood by every supported database, so no dialect mapping is needed.
+const Now Raw = "CURRENT_TIMESTAMP"
+
 var (
 	placeholderRegexp = regexp.MustCompile(`\$(\d+)`)
 	// Common errors
 	ErrEmptyIdentifier = fmt.Errorf("empty identifier not allowed")
 	ErrInvalidDBType   = fmt.Errorf("invalid database type")
 	ErrNoDataProvided  = fmt.Errorf("no data provided")
+	ErrUnsupported     = fmt.Errorf("not supported by database type")
+
+	jsonPathSegmentRegexp = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
 )
 
 func newBuilder(dbType DBType, table string, op string, columns ...string) *QueryBuilder {
-	qb := &QueryBuilder{dbType: dbType, op: op}
+	qb := acquireBuilder()
+	qb.dbType = dbType
+	qb.op = op
 
 	// Validate database type
-	if !dbType.IsValid() {
+	dialect, ok := lookupDialect(dbType)
+	if !ok {
 		qb.err = fmt.Errorf("%w: %s", ErrInvalidDBType, dbType)
 		return qb
 	}
+	qb.dialect = dialect
 
 	// Validate and escape table name
 	if table == "" {
@@ -75,13 +125,14 @@ func newBuilder(dbType DBType, table string, op string, columns ...string) *Quer
 		return qb
 	}
 
-	safeTable, err := EscapeIdentifier(dbType, table)
+	safeTable, err := escapeAliased(dialect, table)
 	if err != nil {
 		qb.err = fmt.Errorf("invalid table name: %w", err)
 		return qb
 	}
 	qb.table = safeTable
-	qb.columns = sanitizeColumns(dbType, columns, &qb.err)
+	qb.columns = sanitizeSelectColumns(dialect, columns, &qb.err)
+	qb.selected = append([]string(nil), columns...)
 	return qb
 }
 
@@ -132,8 +183,8 @@ NewQueryBuilder
 @ Return: *QueryBuilder instance
 */
 func NewQueryBuilder(dbType DBType, table string, columns ...string) *QueryBuilder {
-	qb := &QueryBuilder{dbType: dbType}
-	safeTable, err := EscapeIdentifier(dbType, table)
+	qb := &QueryBuilder{dbType: dbType, dialect: dialectFor(dbType)}
+	safeTable, err := qb.dialect.EscapeIdentifier(table)
 	if err != nil {
 		qb.err = err
 		return qb
@@ -141,7 +192,7 @@ func NewQueryBuilder(dbType DBType, table string, columns ...string) *QueryBuild
 	qb.table = safeTable
 	safeColumns := make([]string, len(columns))
 	for i, col := range columns {
-		safeCol, err := EscapeIdentifier(dbType, col)
+		safeCol, err := qb.dialect.EscapeIdentifier(col)
 		if err != nil {
 			qb.err = err
 			return qb
@@ -169,7 +220,7 @@ func (qb *QueryBuilder) Distinct() *QueryBuilder {
 }
 
 // Aggregate adds an aggregate function to the SELECT columns.
-// Supported functions: COUNT, SUM, AVG, MIN, MAX, etc.
+// Supported functions: COUNT, SUM, AVG, MIN, MAX and those added with RegisterAggregate.
 func (qb *QueryBuilder) Aggregate(function, column string) *QueryBuilder {
 	if qb.err != nil {
 		return qb
@@ -182,6 +233,10 @@ func (qb *QueryBuilder) Aggregate(function, column string) *QueryBuilder {
 		qb.err = fmt.Errorf("aggregate function name cannot be empty")
 		return qb
 	}
+	if !isAllowedAggregate(function) {
+		qb.err = fmt.Errorf("%w: %q", ErrAggregateNotAllowed, function)
+		return qb
+	}
 
 	// Special case: * doesn't need escaping
 	if column == "*" {
@@ -189,7 +244,7 @@ func (qb *QueryBuilder) Aggregate(function, column string) *QueryBuilder {
 		return qb
 	}
 
-	safeCol, err := EscapeIdentifier(qb.dbType, column)
+	safeCol, err := qb.dialect.EscapeIdentifier(column)
 	if err != nil {
 		qb.err = fmt.Errorf("invalid column name for aggregate: %w", err)
 		return qb
@@ -198,6 +253,39 @@ func (qb *QueryBuilder) Aggregate(function, column string) *QueryBuilder {
 	return qb
 }
 
+/*
+Alias
+
+@ name: Alias of the main table
+@ Return: *QueryBuilder selecting from "table AS name"
+
+Use it instead of passing "users u" as the table name.
+*/
+func (qb *QueryBuilder) Alias(name string) *QueryBuilder {
+	if qb.err != nil {
+		return qb
+	}
+	if qb.op == "INSERT" {
+		qb.err = fmt.Errorf("Alias() cannot be used with INSERT operation")
+		return qb
+	}
+	if strings.Contains(qb.table, " ") {
+		qb.err = fmt.Errorf("table %s already has an alias", qb.table)
+		return qb
+	}
+	if strings.Contains(name, ".") {
+		qb.err = fmt.Errorf("%w: alias %q", ErrInvalidIdentifier, name)
+		return qb
+	}
+	safeAlias, err := qb.dialect.EscapeIdentifier(name)
+	if err != nil {
+		qb.err = fmt.Errorf("invalid table alias: %w", err)
+		return qb
+	}
+	qb.table += " AS " + safeAlias
+	return qb
+}
+
 // Select adds additional columns to the SELECT clause.
 // This method can be called multiple times to add more columns.
 func (qb *QueryBuilder) Select(columns ...string) *QueryBuilder {
@@ -208,13 +296,44 @@ func (qb *QueryBuilder) Select(columns ...string) *QueryBuilder {
 		qb.err = fmt.Errorf("Select() can only be used with SELECT queries")
 		return qb
 	}
+	if !qb.checkColumns(columns...) {
+		return qb
+	}
 
-	safeColumns := sanitizeColumns(qb.dbType, columns, &qb.err)
+	safeColumns := sanitizeSelectColumns(qb.dialect, columns, &qb.err)
 	if qb.err != nil {
 		return qb
 	}
 
 	qb.columns = append(qb.columns, safeColumns...)
+	qb.selected = append(qb.selected, columns...)
+	return qb
+}
+
+/*
+SelectRaw
+
+@ expr: Raw SQL expression added to the SELECT list (e.g. "COUNT(p.id) AS post_count")
+@ args: Arguments for the expression placeholders
+@ Return: *QueryBuilder with the expression selected
+
+The expression is appended like Select columns, after the "*" of a builder created without columns.
+It is not escaped. Never build it from user input.
+*/
+func (qb *QueryBuilder) SelectRaw(expr string, args ...interface{}) *QueryBuilder {
+	if qb.err != nil {
+		return qb
+	}
+	if qb.op != "SELECT" {
+		qb.err = fmt.Errorf("SelectRaw() can only be used with SELECT queries")
+		return qb
+	}
+	if strings.TrimSpace(expr) == "" {
+		qb.err = fmt.Errorf("SelectRaw() expression cannot be empty")
+		return qb
+	}
+	qb.columns = append(qb.columns, expr)
+	qb.args = append(qb.args, args...)
 	return qb
 }
 
@@ -228,19 +347,21 @@ func (qb *QueryBuilder) OrWhere(condition string, args ...interface{}) *QueryBui
 		qb.err = fmt.Errorf("condition cannot be empty")
 		return qb
 	}
-
-	startIdx := len(qb.args) + 1
-	updatedCondition := ReplacePlaceholders(qb.dbType, condition, startIdx)
+	if !qb.checkConditionColumn(condition) {
+		return qb
+	}
 
 	// If there are existing conditions, wrap them with the new OR condition
 	if len(qb.conditions) > 0 {
 		lastCondition := qb.conditions[len(qb.conditions)-1]
-		qb.conditions[len(qb.conditions)-1] = fmt.Sprintf("(%s OR %s)", lastCondition, updatedCondition)
+		qb.conditions[len(qb.conditions)-1] = sqlClause{
+			sql:  fmt.Sprintf("(%s OR %s)", lastCondition.sql, condition),
+			args: append(append([]interface{}{}, lastCondition.args...), args...),
+		}
 	} else {
-		qb.conditions = append(qb.conditions, updatedCondition)
+		qb.conditions = append(qb.conditions, sqlClause{sql: condition, args: args})
 	}
 
-	qb.args = append(qb.args, args...)
 	return qb
 }
 
@@ -255,12 +376,12 @@ func (qb *QueryBuilder) LeftJoin(joinTable, onCondition string) *QueryBuilder {
 	if qb.err != nil {
 		return qb
 	}
-	safeTable, err := EscapeIdentifier(qb.dbType, joinTable)
+	safeTable, err := escapeAliased(qb.dialect, joinTable)
 	if err != nil {
 		qb.err = err
 		return qb
 	}
-	qb.joins = append(qb.joins, fmt.Sprintf("LEFT JOIN %s ON %s", safeTable, onCondition))
+	qb.joins = append(qb.joins, sqlClause{sql: fmt.Sprintf("LEFT JOIN %s ON %s", safeTable, onCondition)})
 	return qb
 }
 
@@ -275,12 +396,12 @@ func (qb *QueryBuilder) InnerJoin(joinTable, onCondition string) *QueryBuilder {
 	if qb.err != nil {
 		return qb
 	}
-	safeTable, err := EscapeIdentifier(qb.dbType, joinTable)
+	safeTable, err := escapeAliased(qb.dialect, joinTable)
 	if err != nil {
 		qb.err = err
 		return qb
 	}
-	qb.joins = append(qb.joins, fmt.Sprintf("INNER JOIN %s ON %s", safeTable, onCondition))
+	qb.joins = append(qb.joins, sqlClause{sql: fmt.Sprintf("INNER JOIN %s ON %s", safeTable, onCondition)})
 	return qb
 }
 
@@ -295,18 +416,39 @@ func (qb *QueryBuilder) RightJoin(joinTable, onCondition string) *QueryBuilder {
 	if qb.err != nil {
 		return qb
 	}
-	safeTable, err := EscapeIdentifier(qb.dbType, joinTable)
+	safeTable, err := escapeAliased(qb.dialect, joinTable)
 	if err != nil {
 		qb.err = err
 		return qb
 	}
-	qb.joins = append(qb.joins, fmt.Sprintf("RIGHT JOIN %s ON %s", safeTable, onCondition))
+	qb.joins = append(qb.joins, sqlClause{sql: fmt.Sprintf("RIGHT JOIN %s ON %s", safeTable, onCondition)})
 	return qb
 }
 
 // Where adds a WHERE condition to the query.
 // Conditions are combined with AND. Use ? as placeholders for parameters.
 func (qb *QueryBuilder) Where(condition string, args ...interface{}) *QueryBuilder {
+	if qb.err != nil {
+		return qb
+	}
+	if !qb.checkConditionColumn(condition) {
+		return qb
+	}
+	return qb.WhereRaw(condition, args...)
+}
+
+/*
+WhereRaw
+
+@ condition: SQL predicate written as is, with ? placeholders for its arguments
+@ args: Arguments for the predicate placeholders
+@ Return: *QueryBuilder with the condition added
+
+For predicates the structured helpers cannot express, such as tsrange(starts_at, ends_at) && tsrange(?, ?).
+Identifiers in the condition are neither validated nor escaped, nor checked against AllowColumns,
+so never build it from user input; pass values as args.
+*/
+func (qb *QueryBuilder) WhereRaw(condition string, args ...interface{}) *QueryBuilder {
 	if qb.err != nil {
 		return qb
 	}
@@ -315,10 +457,7 @@ func (qb *QueryBuilder) Where(condition string, args ...interface{}) *QueryBuild
 		return qb
 	}
 
-	startIdx := len(qb.args) + 1
-	updatedCondition := ReplacePlaceholders(qb.dbType, condition, startIdx)
-	qb.conditions = append(qb.conditions, updatedCondition)
-	qb.args = append(qb.args, args...)
+	qb.conditions = append(qb.conditions, sqlClause{sql: condition, args: args})
 	return qb
 }
 
@@ -326,21 +465,99 @@ func (qb *QueryBuilder) Where(condition string, args ...interface{}) *QueryBuild
 WhereIn
 
 @ column: Column name for IN clause
-@ values: Values for the IN clause
+@ values: Values for the IN clause, at least one
 @ Return: *QueryBuilder with IN clause added
 */
 func (qb *QueryBuilder) WhereIn(column string, values []interface{}) *QueryBuilder {
-	if qb.err != nil {
+	if !qb.checkColumns(column) {
+		return qb
+	}
+	if len(values) == 0 {
+		qb.err = fmt.Errorf("WhereIn() requires at least one value")
+		return qb
+	}
+	safeCol, err := qb.dialect.EscapeIdentifier(column)
+	if err != nil {
+		qb.err = err
+		return qb
+	}
+	qb.conditions = append(qb.conditions, sqlClause{
+		sql:  fmt.Sprintf("%s IN (%s)", safeCol, questionMarks(len(values))),
+		args: append([]interface{}(nil), values...),
+	})
+	return qb
+}
+
+/*
+WhereInArray
+
+@ column: Column name for the membership check
+@ values: Values to match
+@ Return: *QueryBuilder with the membership condition added
+
+PostgreSQL binds every value as a single array argument (column = ANY($1)), staying clear of bind parameter limits for long lists.
+Other databases fall back to WhereIn.
+*/
+func (qb *QueryBuilder) WhereInArray(column string, values []interface{}) *QueryBuilder {
+	if !qb.checkColumns(column) {
 		return qb
 	}
-	safeCol, err := EscapeIdentifier(qb.dbType, column)
+	if qb.dbType != PostgreSQL {
+		return qb.WhereIn(column, values)
+	}
+	safeCol, err := qb.dialect.EscapeIdentifier(column)
 	if err != nil {
 		qb.err = err
 		return qb
 	}
-	placeholders := GeneratePlaceholders(qb.dbType, len(qb.args)+1, len(values))
-	qb.conditions = append(qb.conditions, fmt.Sprintf("%s IN (%s)", safeCol, placeholders))
-	qb.args = append(qb.args, values...)
+	qb.conditions = append(qb.conditions, sqlClause{
+		sql:  safeCol + " = ANY(?)",
+		args: []interface{}{pq.Array(values)},
+	})
+	return qb
+}
+
+/*
+WhereFullText
+
+@ columns: Columns searched
+@ query: Search text
+@ Return: *QueryBuilder with full-text search condition added
+
+Builds to_tsvector(...) @@ plainto_tsquery(?) for PostgreSQL and MATCH (...) AGAINST (? IN NATURAL LANGUAGE MODE)
+for MariaDB and Mysql, which need a FULLTEXT index on exactly these columns. Sqlite only searches FTS5 virtual tables and is not supported.
+*/
+func (qb *QueryBuilder) WhereFullText(columns []string, query string) *QueryBuilder {
+	if qb.err != nil {
+		return qb
+	}
+	if !qb.dbType.Supports(FeatureFullText) {
+		qb.err = fmt.Errorf("WhereFullText() %w: %s", ErrUnsupported, qb.dbType)
+		return qb
+	}
+	if len(columns) == 0 {
+		qb.err = fmt.Errorf("WhereFullText() requires at least one column")
+		return qb
+	}
+	if !qb.checkColumns(columns...) {
+		return qb
+	}
+	safeColumns := sanitizeColumns(qb.dialect, columns, &qb.err)
+	if qb.err != nil {
+		return qb
+	}
+
+	var condition string
+	if qb.dbType == PostgreSQL {
+		document := safeColumns[0]
+		if len(safeColumns) > 1 {
+			document = "concat_ws(' ', " + strings.Join(safeColumns, ", ") + ")"
+		}
+		condition = "to_tsvector(" + document + ") @@ plainto_tsquery(?)"
+	} else {
+		condition = "MATCH (" + strings.Join(safeColumns, ", ") + ") AGAINST (? IN NATURAL LANGUAGE MODE)"
+	}
+	qb.conditions = append(qb.conditions, sqlClause{sql: condition, args: []interface{}{query}})
 	return qb
 }
 
@@ -353,22 +570,97 @@ WhereBetween
 @ Return: *QueryBuilder with BETWEEN clause added
 */
 func (qb *QueryBuilder) WhereBetween(column string, start, end interface{}) *QueryBuilder {
-	if qb.err != nil {
+	return qb.whereRange(column, "%s BETWEEN ? AND ?", start, end)
+}
+
+/*
+WhereNotBetween
+
+@ column: Column name for NOT BETWEEN clause
+@ start: Start value
+@ end: End value
+@ Return: *QueryBuilder with NOT BETWEEN clause added
+*/
+func (qb *QueryBuilder) WhereNotBetween(column string, start, end interface{}) *QueryBuilder {
+	return qb.whereRange(column, "%s NOT BETWEEN ? AND ?", start, end)
+}
+
+/*
+WhereBetweenExclusive
+
+@ column: Column name compared with the bounds
+@ start: Lower bound, excluded
+@ end: Upper bound, excluded
+@ Return: *QueryBuilder with column > start AND column < end added
+*/
+func (qb *QueryBuilder) WhereBetweenExclusive(column string, start, end interface{}) *QueryBuilder {
+	return qb.whereRange(column, "(%[1]s > ? AND %[1]s < ?)", start, end)
+}
+
+/*
+WhereBetweenTime
+
+@ column: Timestamp column
+@ start: Start of the range
+@ end: End of the range
+@ Return: *QueryBuilder with BETWEEN clause added, or an error if end is before start
+*/
+func (qb *QueryBuilder) WhereBetweenTime(column string, start, end time.Time) *QueryBuilder {
+	if qb.err == nil && end.Before(start) {
+		qb.err = fmt.Errorf("WhereBetweenTime() end %s is before start %s", end, start)
+		return qb
+	}
+	return qb.WhereBetween(column, start, end)
+}
+
+// whereRange adds a condition comparing an escaped column with two bound values.
+// format receives the escaped column as its only operand.
+func (qb *QueryBuilder) whereRange(column, format string, start, end interface{}) *QueryBuilder {
+	if !qb.checkColumns(column) {
 		return qb
 	}
-	safeCol, err := EscapeIdentifier(qb.dbType, column)
+	safeCol, err := qb.dialect.EscapeIdentifier(column)
 	if err != nil {
 		qb.err = err
 		return qb
 	}
-	placeholders := GeneratePlaceholders(qb.dbType, len(qb.args)+1, 2)
-	placeholderSlices := strings.Split(placeholders, ", ")
-	if len(placeholderSlices) != 2 {
-		qb.err = fmt.Errorf("failed to generate placeholders for BETWEEN")
+	qb.conditions = append(qb.conditions, sqlClause{
+		sql:  fmt.Sprintf(format, safeCol),
+		args: []interface{}{start, end},
+	})
+	return qb
+}
+
+/*
+WhereNullSafeEq
+
+@ column: Column name compared with the value
+@ value: Value matched, nil matches NULL columns
+@ Return: *QueryBuilder with null-safe equality condition added
+
+Builds <=> for MariaDB and Mysql, IS for Sqlite and IS NOT DISTINCT FROM for PostgreSQL.
+Sqlite only understands IS NOT DISTINCT FROM since 3.39.0, while IS is null-safe in every version.
+*/
+func (qb *QueryBuilder) WhereNullSafeEq(column string, value interface{}) *QueryBuilder {
+	if !qb.checkColumns(column) {
+		return qb
+	}
+	safeCol, err := qb.dialect.EscapeIdentifier(column)
+	if err != nil {
+		qb.err = err
 		return qb
 	}
-	qb.conditions = append(qb.conditions, fmt.Sprintf("%s BETWEEN %s AND %s", safeCol, placeholderSlices[0], placeholderSlices[1]))
-	qb.args = append(qb.args, start, end)
+	operator := "IS NOT DISTINCT FROM"
+	switch qb.dbType {
+	case MariaDB, Mysql:
+		operator = "<=>"
+	case Sqlite:
+		operator = "IS"
+	}
+	qb.conditions = append(qb.conditions, sqlClause{
+		sql:  fmt.Sprintf("%s %s ?", safeCol, operator),
+		args: []interface{}{value},
+	})
 	return qb
 }
 
@@ -378,27 +670,72 @@ AddWhereIfNotEmpty
 @ column: Column name
 @ value: arguments
 @ Return: *QueryBuilder
+
+The condition is skipped when value is empty: nil, a nil pointer, an empty string, slice, map or array,
+or a zero number once SkipZeroValues was called. Non-nil pointers are judged by the value they point to.
+Numbers, booleans and structs such as time.Time are never empty by default.
 */
 func (qb *QueryBuilder) AddWhereIfNotEmpty(condition string, value interface{}) *QueryBuilder {
-	if value == nil {
+	return qb.AddWhereIf(condition, value, qb.notEmpty)
+}
+
+/*
+AddWhereIf
+
+@ condition: Condition with a single ? placeholder
+@ value: Argument for the placeholder
+@ predicate: Reports whether the condition is applied for value
+@ Return: *QueryBuilder
+*/
+func (qb *QueryBuilder) AddWhereIf(condition string, value interface{}, predicate func(interface{}) bool) *QueryBuilder {
+	if qb.err != nil {
 		return qb
 	}
-
-	switch v := value.(type) {
-	case string:
-		if v == "" {
-			return qb
-		}
-	case *string:
-		if v == nil || *v == "" {
-			return qb
-		}
-		// 필요에 따라 다른 타입도 처리
+	if predicate == nil {
+		qb.err = fmt.Errorf("AddWhereIf() predicate cannot be nil")
+		return qb
+	}
+	if !predicate(value) {
+		return qb
 	}
-
 	return qb.Where(condition, value)
 }
 
+// notEmpty is the AddWhereIfNotEmpty predicate.
+func (qb *QueryBuilder) notEmpty(value interface{}) bool {
+	return !isEmptyValue(reflect.ValueOf(value), qb.skipZero)
+}
+
+/*
+SkipZeroValues
+
+@ Return: *QueryBuilder whose AddWhereIfNotEmpty skips conditions on zero numbers
+
+For filters where 0 means "not set", such as an optional id from a form.
+*/
+func (qb *QueryBuilder) SkipZeroValues() *QueryBuilder {
+	qb.skipZero = true
+	return qb
+}
+
+// isEmptyValue reports whether AddWhereIfNotEmpty skips a value.
+func isEmptyValue(v reflect.Value, skipZero bool) bool {
+	switch v.Kind() {
+	case reflect.Invalid:
+		return true
+	case reflect.Pointer, reflect.Interface:
+		return v.IsNil() || isEmptyValue(v.Elem(), skipZero)
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return v.Len() == 0
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return skipZero && v.IsZero()
+	default:
+		return false
+	}
+}
+
 /*
 GroupBy
 
@@ -409,8 +746,11 @@ func (qb *QueryBuilder) GroupBy(columns ...string) *QueryBuilder {
 	if qb.err != nil {
 		return qb
 	}
+	if !qb.checkColumns(columns...) {
+		return qb
+	}
 	for _, col := range columns {
-		safeCol, err := EscapeIdentifier(qb.dbType, col)
+		safeCol, err := qb.dialect.EscapeIdentifier(col)
 		if err != nil {
 			qb.err = err
 			return qb
@@ -431,9 +771,35 @@ func (qb *QueryBuilder) Having(condition string, args ...interface{}) *QueryBuil
 	if qb.err != nil {
 		return qb
 	}
-	updatedCondition := ReplacePlaceholders(qb.dbType, condition, len(qb.args)+1)
-	qb.having = append(qb.having, updatedCondition)
-	qb.args = append(qb.args, args...)
+	qb.having = append(qb.having, sqlClause{sql: condition, args: args})
+	return qb
+}
+
+/*
+HavingIn
+
+@ expr: Aggregate expression tested for membership (e.g. "COUNT(*)")
+@ values: Values for the IN list
+@ Return: *QueryBuilder with HAVING expr IN (...) added
+
+The expression is not escaped. Never build it from user input.
+*/
+func (qb *QueryBuilder) HavingIn(expr string, values []interface{}) *QueryBuilder {
+	if qb.err != nil {
+		return qb
+	}
+	if strings.TrimSpace(expr) == "" {
+		qb.err = fmt.Errorf("HavingIn() expression cannot be empty")
+		return qb
+	}
+	if len(values) == 0 {
+		qb.err = fmt.Errorf("HavingIn() requires at least one value")
+		return qb
+	}
+	qb.having = append(qb.having, sqlClause{
+		sql:  fmt.Sprintf("%s IN (%s)", expr, questionMarks(len(values))),
+		args: append([]interface{}(nil), values...),
+	})
 	return qb
 }
 
@@ -444,23 +810,96 @@ OrderBy
 @ direction: Order direction ("ASC" or "DESC")
 @ allowedColumns: Map of allowed columns for ordering
 @ Return: *QueryBuilder with ORDER BY clause added
+
+A column missing from allowedColumns is replaced with the DefaultOrderColumn of the builder, or else "id".
 */
 func (qb *QueryBuilder) OrderBy(column, direction string, allowedColumns map[string]bool) *QueryBuilder {
 	if qb.err != nil {
 		return qb
 	}
-	direction = ValidateDirection(direction)
-	if allowedColumns != nil {
-		if _, ok := allowedColumns[column]; !ok {
+	if allowedColumns != nil && !allowedColumns[column] {
+		column = qb.orderFallback
+		if column == "" {
 			column = "id"
 		}
 	}
-	safeCol, err := EscapeIdentifier(qb.dbType, column)
+	return qb.setOrderBy(column, direction)
+}
+
+/*
+DefaultOrderColumn
+
+@ column: Column used by later OrderBy calls when the allow-list rejects the requested column
+@ Return: *QueryBuilder with the fallback order column set
+*/
+func (qb *QueryBuilder) DefaultOrderColumn(column string) *QueryBuilder {
+	if qb.err != nil {
+		return qb
+	}
+	if _, err := qb.dialect.EscapeIdentifier(column); err != nil {
+		qb.err = fmt.Errorf("invalid default order column: %w", err)
+		return qb
+	}
+	qb.orderFallback = column
+	return qb
+}
+
+/*
+OrderByStrict
+
+@ column: Column name to order by
+@ direction: Order direction ("ASC" or "DESC")
+@ allowedColumns: Map of allowed columns for ordering
+@ Return: *QueryBuilder with ORDER BY clause added
+
+Unlike OrderBy, a column missing from allowedColumns is an error instead of falling back to the default order column.
+*/
+func (qb *QueryBuilder) OrderByStrict(column, direction string, allowedColumns map[string]bool) *QueryBuilder {
+	if qb.err != nil {
+		return qb
+	}
+	if !allowedColumns[column] {
+		qb.err = fmt.Errorf("%w: %q", ErrDisallowedOrderColumn, column)
+		return qb
+	}
+	return qb.setOrderBy(column, direction)
+}
+
+func (qb *QueryBuilder) setOrderBy(column, direction string) *QueryBuilder {
+	if !qb.checkColumns(column) {
+		return qb
+	}
+	safeCol, err := qb.dialect.EscapeIdentifier(column)
 	if err != nil {
 		qb.err = err
 		return qb
 	}
-	qb.orderBy = fmt.Sprintf("%s %s", safeCol, direction)
+	qb.orderBy = fmt.Sprintf("%s %s", safeCol, ValidateDirection(direction))
+	return qb
+}
+
+// ErrDisallowedOrderColumn is returned by OrderByStrict for columns missing from the allow-list.
+var ErrDisallowedOrderColumn = fmt.Errorf("order column not allowed")
+
+/*
+OrderByRandom
+
+@ Return: *QueryBuilder ordered randomly, typically followed by Limit to sample rows
+
+Builds RANDOM() for PostgreSQL and Sqlite, RAND() for MariaDB and Mysql and NEWID() for SQLServer.
+*/
+func (qb *QueryBuilder) OrderByRandom() *QueryBuilder {
+	if qb.err != nil {
+		return qb
+	}
+	switch qb.dbType {
+	case MariaDB, Mysql:
+		qb.orderBy = "RAND()"
+	case SQLServer:
+		qb.orderBy = "NEWID()"
+	default:
+		qb.orderBy = "RANDOM()"
+	}
 	return qb
 }
 
@@ -471,6 +910,16 @@ Limit
 @ Return: *QueryBuilder with LIMIT set
 */
 func (qb *QueryBuilder) Limit(limit int) *QueryBuilder {
+	return qb.Limit64(int64(limit))
+}
+
+/*
+Limit64
+
+@ limit: Maximum number of rows to return
+@ Return: *QueryBuilder with LIMIT set
+*/
+func (qb *QueryBuilder) Limit64(limit int64) *QueryBuilder {
 	if qb.err != nil {
 		return qb
 	}
@@ -485,6 +934,16 @@ Offset
 @ Return: *QueryBuilder with OFFSET set
 */
 func (qb *QueryBuilder) Offset(offset int) *QueryBuilder {
+	return qb.Offset64(int64(offset))
+}
+
+/*
+Offset64
+
+@ offset: Number of rows to skip, beyond the int range of 32-bit platforms if needed
+@ Return: *QueryBuilder with OFFSET set
+*/
+func (qb *QueryBuilder) Offset64(offset int64) *QueryBuilder {
 	if qb.err != nil {
 		return qb
 	}
@@ -492,258 +951,1643 @@ func (qb *QueryBuilder) Offset(offset int) *QueryBuilder {
 	return qb
 }
 
-// Values adds data for INSERT operations.
-// Data should be a map of column names to values.
-func (qb *QueryBuilder) Values(data map[string]interface{}) *QueryBuilder {
+/*
+Top
+
+@ n: Maximum number of rows to return
+@ Return: *QueryBuilder with SELECT TOP n
+
+Unlike Limit on SQLServer, TOP needs no ORDER BY. It cannot be combined with Limit or Offset.
+*/
+func (qb *QueryBuilder) Top(n int) *QueryBuilder {
 	if qb.err != nil {
 		return qb
 	}
-	if qb.op != "INSERT" {
-		qb.err = fmt.Errorf("Values() can only be used with INSERT operation")
+	if !qb.dbType.Supports(FeatureTop) {
+		qb.err = fmt.Errorf("Top() %w: %s", ErrUnsupported, qb.dbType)
 		return qb
 	}
-	if len(data) == 0 {
+	if qb.op != "SELECT" {
+		qb.err = fmt.Errorf("Top() can only be used with SELECT queries")
+		return qb
+	}
+	if n <= 0 {
+		qb.err = fmt.Errorf("Top() requires a positive row count, got %d", n)
+		return qb
+	}
+	qb.top = int64(n)
+	return qb
+}
+
+// checkPaging reports paging combinations the database rejects.
+// SQLServer pages with OFFSET ... FETCH, which is only valid after an ORDER BY and never together with TOP.
+func (qb *QueryBuilder) checkPaging() error {
+	if qb.dbType != SQLServer || (qb.limit <= 0 && qb.offset <= 0) {
+		return nil
+	}
+	if qb.top > 0 {
+		return fmt.Errorf("Top() cannot be combined with Limit() or Offset()")
+	}
+	if qb.orderBy == "" {
+		return fmt.Errorf("Limit() and Offset() require ORDER BY on %s", qb.dbType)
+	}
+	return nil
+}
+
+// Values adds data for INSERT operations.
+// Data should be a map of column names to values.
+func (qb *QueryBuilder) Values(data map[string]interface{}) *QueryBuilder {
+	if qb.err != nil {
+		return qb
+	}
+	if qb.op != "INSERT" {
+		qb.err = fmt.Errorf("Values() can only be used with INSERT operation")
+		return qb
+	}
+	if len(data) == 0 {
 		qb.err = fmt.Errorf("Values() requires at least one column-value pair")
 		return qb
 	}
-	qb.data = data
-	return qb
+	for col, val := range data {
+		if raw, ok := val.(Raw); ok && strings.TrimSpace(string(raw)) == "" {
+			qb.err = fmt.Errorf("Values() raw expression for %s cannot be empty", col)
+			return qb
+		}
+	}
+	qb.data = data
+	return qb
+}
+
+// DefaultValues inserts a row made of the column defaults when no data is given,
+// for tables whose columns all have defaults. Values and ValuesRows take precedence.
+func (qb *QueryBuilder) DefaultValues() *QueryBuilder {
+	if qb.err != nil {
+		return qb
+	}
+	if qb.op != "INSERT" {
+		qb.err = fmt.Errorf("DefaultValues() can only be used with INSERT operation")
+		return qb
+	}
+	qb.defaults = true
+	return qb
+}
+
+// ValuesRows adds several rows for a single multi-row INSERT.
+// Every row holds its values in the order of columns.
+func (qb *QueryBuilder) ValuesRows(columns []string, rows [][]interface{}) *QueryBuilder {
+	if qb.err != nil {
+		return qb
+	}
+	if qb.op != "INSERT" {
+		qb.err = fmt.Errorf("ValuesRows() can only be used with INSERT operation")
+		return qb
+	}
+	if len(columns) == 0 || len(rows) == 0 {
+		qb.err = fmt.Errorf("ValuesRows() requires at least one column and one row")
+		return qb
+	}
+	for i, row := range rows {
+		if len(row) != len(columns) {
+			qb.err = fmt.Errorf("ValuesRows() row %d has %d values, expected %d", i, len(row), len(columns))
+			return qb
+		}
+	}
+	safeColumns := sanitizeColumns(qb.dialect, columns, &qb.err)
+	if qb.err != nil {
+		return qb
+	}
+	qb.rowColumns = safeColumns
+	qb.rows = rows
+	return qb
+}
+
+/*
+ValuesOrdered
+
+@ columns: Columns in the order they are written
+@ values: Values in the order of columns; Raw values are written literally
+@ Return: *QueryBuilder inserting one row with exactly that column order
+
+Unlike Values, whose map columns are sorted, the order is kept verbatim.
+*/
+func (qb *QueryBuilder) ValuesOrdered(columns []string, values []interface{}) *QueryBuilder {
+	if qb.err != nil {
+		return qb
+	}
+	if err := checkOrderedData("ValuesOrdered", columns, values); err != nil {
+		qb.err = err
+		return qb
+	}
+	return qb.ValuesRows(columns, [][]interface{}{values})
+}
+
+// Set adds data for UPDATE operations.
+// Data should be a map of column names to values.
+func (qb *QueryBuilder) Set(data map[string]interface{}) *QueryBuilder {
+	if qb.err != nil {
+		return qb
+	}
+	if qb.op != "UPDATE" {
+		qb.err = fmt.Errorf("Set() can only be used with UPDATE operation")
+		return qb
+	}
+	if len(data) == 0 {
+		qb.err = fmt.Errorf("Set() requires at least one column-value pair")
+		return qb
+	}
+	for col, val := range data {
+		if raw, ok := val.(Raw); ok && strings.TrimSpace(string(raw)) == "" {
+			qb.err = fmt.Errorf("Set() raw expression for %s cannot be empty", col)
+			return qb
+		}
+	}
+	qb.data = data
+	return qb
+}
+
+/*
+SetOrdered
+
+@ columns: Columns in the order they are assigned
+@ values: Values in the order of columns; Raw values are written literally
+@ Return: *QueryBuilder with the assignments added in exactly that order
+
+Unlike Set, whose map columns are sorted, the order is kept verbatim.
+*/
+func (qb *QueryBuilder) SetOrdered(columns []string, values []interface{}) *QueryBuilder {
+	if qb.err != nil {
+		return qb
+	}
+	if qb.op != "UPDATE" {
+		qb.err = fmt.Errorf("SetOrdered() can only be used with UPDATE operation")
+		return qb
+	}
+	if err := checkOrderedData("SetOrdered", columns, values); err != nil {
+		qb.err = err
+		return qb
+	}
+	for i, col := range columns {
+		if raw, ok := values[i].(Raw); ok {
+			qb.SetRaw(col, string(raw))
+		} else {
+			qb.SetRaw(col, "?", values[i])
+		}
+	}
+	return qb
+}
+
+// checkOrderedData validates the parallel column and value slices of ValuesOrdered and SetOrdered.
+func checkOrderedData(method string, columns []string, values []interface{}) error {
+	if len(columns) == 0 {
+		return fmt.Errorf("%s() requires at least one column-value pair", method)
+	}
+	if len(columns) != len(values) {
+		return fmt.Errorf("%s() has %d columns but %d values", method, len(columns), len(values))
+	}
+	for i, val := range values {
+		if raw, ok := val.(Raw); ok && strings.TrimSpace(string(raw)) == "" {
+			return fmt.Errorf("%s() raw expression for %s cannot be empty", method, columns[i])
+		}
+	}
+	return nil
+}
+
+/*
+Increment
+
+@ column: Column to increase
+@ amount: Amount added to the column
+@ Return: *QueryBuilder with "column = column + ?" assignment added
+*/
+func (qb *QueryBuilder) Increment(column string, amount interface{}) *QueryBuilder {
+	return qb.addArithmeticSet("Increment", column, "+", amount)
+}
+
+/*
+Decrement
+
+@ column: Column to decrease
+@ amount: Amount subtracted from the column
+@ Return: *QueryBuilder with "column = column - ?" assignment added
+*/
+func (qb *QueryBuilder) Decrement(column string, amount interface{}) *QueryBuilder {
+	return qb.addArithmeticSet("Decrement", column, "-", amount)
+}
+
+/*
+SetRaw
+
+@ column: Column to assign
+@ expr: Raw SQL expression with "?" placeholders
+@ args: Arguments for the expression placeholders
+@ Return: *QueryBuilder with "column = expr" assignment added
+*/
+func (qb *QueryBuilder) SetRaw(column, expr string, args ...interface{}) *QueryBuilder {
+	if qb.err != nil {
+		return qb
+	}
+	if qb.op != "UPDATE" {
+		qb.err = fmt.Errorf("SetRaw() can only be used with UPDATE operation")
+		return qb
+	}
+	if strings.TrimSpace(expr) == "" {
+		qb.err = fmt.Errorf("SetRaw() expression cannot be empty")
+		return qb
+	}
+	safeCol, err := qb.dialect.EscapeIdentifier(column)
+	if err != nil {
+		qb.err = err
+		return qb
+	}
+	qb.sets = append(qb.sets, sqlClause{sql: safeCol + " = " + expr, args: args})
+	return qb
+}
+
+/*
+SetJSONPath
+
+@ column: jsonb column to update
+@ path: Dot separated key path inside the document (e.g. "profile.city")
+@ value: Value stored at the path, marshaled to JSON unless it is a json.RawMessage
+@ Return: *QueryBuilder with "column = jsonb_set(column, '{path}', ?::jsonb)" assignment added
+
+Only PostgreSQL is supported.
+*/
+func (qb *QueryBuilder) SetJSONPath(column, path string, value interface{}) *QueryBuilder {
+	if qb.err != nil {
+		return qb
+	}
+	if !qb.dbType.Supports(FeatureJSONB) {
+		qb.err = fmt.Errorf("SetJSONPath() %w: %s", ErrUnsupported, qb.dbType)
+		return qb
+	}
+	segments := strings.Split(path, ".")
+	for _, segment := range segments {
+		if !jsonPathSegmentRegexp.MatchString(segment) {
+			qb.err = fmt.Errorf("invalid JSON path: %q", path)
+			return qb
+		}
+	}
+
+	var doc []byte
+	if raw, ok := value.(json.RawMessage); ok {
+		doc = raw
+	} else {
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			qb.err = fmt.Errorf("marshal JSON path value error: %w", err)
+			return qb
+		}
+		doc = encoded
+	}
+
+	safeCol, err := qb.dialect.EscapeIdentifier(column)
+	if err != nil {
+		qb.err = err
+		return qb
+	}
+	expr := fmt.Sprintf("jsonb_set(%s, '{%s}', ?::jsonb)", safeCol, strings.Join(segments, ","))
+	return qb.SetRaw(column, expr, string(doc))
+}
+
+func (qb *QueryBuilder) addArithmeticSet(method, column, operator string, amount interface{}) *QueryBuilder {
+	if qb.err != nil {
+		return qb
+	}
+	if qb.op != "UPDATE" {
+		qb.err = fmt.Errorf("%s() can only be used with UPDATE operation", method)
+		return qb
+	}
+	safeCol, err := qb.dialect.EscapeIdentifier(column)
+	if err != nil {
+		qb.err = err
+		return qb
+	}
+	qb.sets = append(qb.sets, sqlClause{
+		sql:  fmt.Sprintf("%s = %s %s ?", safeCol, safeCol, operator),
+		args: []interface{}{amount},
+	})
+	return qb
+}
+
+/*
+Returning
+
+@ columns: Columns or expressions of the RETURNING clause (PostgreSQL, MariaDB and SQLite)
+@ Return: *QueryBuilder with RETURNING clause set
+*/
+func (qb *QueryBuilder) Returning(columns ...string) *QueryBuilder {
+	if qb.err != nil {
+		return qb
+	}
+	if qb.op != "INSERT" {
+		qb.err = fmt.Errorf("Returning() can only be used with INSERT operation")
+		return qb
+	}
+	if !qb.dbType.Supports(FeatureReturning) {
+		qb.err = fmt.Errorf("Returning() %w: %s", ErrUnsupported, qb.dbType)
+		return qb
+	}
+	if len(columns) == 0 {
+		qb.err = fmt.Errorf("Returning() requires at least one column")
+		return qb
+	}
+	qb.returning = strings.Join(columns, ", ")
+	return qb
+}
+
+/*
+SoftDelete
+
+@ column: Timestamp column marking a row as deleted
+@ Return: *QueryBuilder with soft-delete enabled
+
+DELETE queries are rewritten into an UPDATE setting the column to CURRENT_TIMESTAMP,
+and SELECT/UPDATE/DELETE queries only match rows where the column IS NULL.
+The filter is qualified with the table alias, or the table name, so it stays unambiguous with joins.
+*/
+func (qb *QueryBuilder) SoftDelete(column string) *QueryBuilder {
+	if qb.err != nil {
+		return qb
+	}
+	safeCol, err := qb.dialect.EscapeIdentifier(column)
+	if err != nil {
+		qb.err = fmt.Errorf("invalid soft-delete column: %w", err)
+		return qb
+	}
+	qb.softDeleteColumn = safeCol
+	return qb
+}
+
+/*
+WithTrashed
+
+@ Return: *QueryBuilder including soft-deleted rows
+*/
+func (qb *QueryBuilder) WithTrashed() *QueryBuilder {
+	if qb.err != nil {
+		return qb
+	}
+	qb.withTrashed = true
+	return qb
+}
+
+/*
+OptimisticLock
+
+@ versionColumn: Version column used for optimistic locking
+@ currentVersion: Version the caller expects the row to have
+@ Return: *QueryBuilder with optimistic locking enabled
+
+The UPDATE only matches rows with the expected version and increments the version.
+A RowsAffected of 0 means the row was modified concurrently.
+*/
+func (qb *QueryBuilder) OptimisticLock(versionColumn string, currentVersion int) *QueryBuilder {
+	if qb.err != nil {
+		return qb
+	}
+	if qb.op != "UPDATE" {
+		qb.err = fmt.Errorf("OptimisticLock() can only be used with UPDATE operation")
+		return qb
+	}
+	safeCol, err := qb.dialect.EscapeIdentifier(versionColumn)
+	if err != nil {
+		qb.err = fmt.Errorf("invalid version column: %w", err)
+		return qb
+	}
+	qb.lockColumn = safeCol
+	qb.lockVersion = currentVersion
+	return qb
+}
+
+/*
+WithPlaceholderStyle
+
+@ style: Placeholder style used instead of the database type's default
+@ Return: *QueryBuilder rendering placeholders in the given style
+*/
+func (qb *QueryBuilder) WithPlaceholderStyle(style PlaceholderStyle) *QueryBuilder {
+	if qb.err != nil {
+		return qb
+	}
+	if !style.IsValid() {
+		qb.err = fmt.Errorf("invalid placeholder style: %d", style)
+		return qb
+	}
+	if styled, ok := qb.dialect.(styledDialect); ok {
+		qb.dialect = styled.Dialect
+	}
+	qb.dialect = styledDialect{Dialect: qb.dialect, style: style}
+	return qb
+}
+
+/*
+ReplacePlaceholders
+
+@ input: Condition string with "?" placeholders
+@ start: Starting index for placeholders
+@ Return: Condition string with placeholders in the builder's style
+*/
+func (qb *QueryBuilder) ReplacePlaceholders(input string, start int) string {
+	return replacePlaceholders(qb.dialect, input, start)
+}
+
+/*
+GeneratePlaceholders
+
+@ start: Starting index for placeholders
+@ count: Number of placeholders to generate
+@ Return: String of placeholders in the builder's style separated by comma
+*/
+func (qb *QueryBuilder) GeneratePlaceholders(start, count int) string {
+	return generatePlaceholders(qb.dialect, start, count)
+}
+
+/*
+Clone
+
+@ Return: Independent copy of the *QueryBuilder
+*/
+func (qb *QueryBuilder) Clone() *QueryBuilder {
+	clone := *qb
+	clone.columns = append([]string(nil), qb.columns...)
+	clone.selected = append([]string(nil), qb.selected...)
+	clone.joins = append([]sqlClause(nil), qb.joins...)
+	clone.ctes = append([]sqlClause(nil), qb.ctes...)
+	clone.rowColumns = append([]string(nil), qb.rowColumns...)
+	clone.rows = append([][]interface{}(nil), qb.rows...)
+	clone.conditions = append([]sqlClause(nil), qb.conditions...)
+	clone.groupBy = append([]string(nil), qb.groupBy...)
+	clone.having = append([]sqlClause(nil), qb.having...)
+	clone.sets = append([]sqlClause(nil), qb.sets...)
+	if qb.conflict != nil {
+		conflict := *qb.conflict
+		clone.conflict = &conflict
+	}
+	clone.args = append([]interface{}(nil), qb.args...)
+	if qb.data != nil {
+		clone.data = make(map[string]interface{}, len(qb.data))
+		for col, val := range qb.data {
+			clone.data[col] = val
+		}
+	}
+	return &clone
+}
+
+/*
+CountQuery
+
+@ Return: Clone of the SELECT builder counting its matching rows
+
+The clone keeps tables, joins and conditions but drops ORDER BY, LIMIT, OFFSET and TOP.
+A DISTINCT, GROUP BY or HAVING query is counted as SELECT COUNT(*) FROM (query) AS t,
+so the result is the number of distinct rows or groups.
+*/
+func (qb *QueryBuilder) CountQuery() *QueryBuilder {
+	clone := qb.Clone()
+	if clone.err != nil {
+		return clone
+	}
+	if clone.op != "SELECT" {
+		clone.err = fmt.Errorf("CountQuery() can only be used with SELECT queries")
+		return clone
+	}
+	clone.orderBy = ""
+	clone.limit = 0
+	clone.offset = 0
+	clone.top = 0
+	clone.explain = ""
+
+	if clone.distinct || len(clone.groupBy) > 0 || len(clone.having) > 0 {
+		count := &QueryBuilder{
+			op:        "SELECT",
+			dbType:    clone.dbType,
+			dialect:   clone.dialect,
+			columns:   []string{"COUNT(*)"},
+			ctes:      clone.ctes,
+			recursive: clone.recursive,
+		}
+		clone.ctes = nil
+		clone.recursive = false
+		derived, err := derivedTable(clone.dialect, clone, "t")
+		if err != nil {
+			count.err = err
+			return count
+		}
+		count.from = &derived
+		return count
+	}
+
+	clone.columns = []string{"COUNT(*)"}
+	clone.args = nil
+	return clone
+}
+
+/*
+Explain
+
+@ Return: *QueryBuilder whose Build output is prefixed with EXPLAIN
+
+Sqlite uses EXPLAIN QUERY PLAN, since its plain EXPLAIN lists virtual machine opcodes.
+SQLServer has no EXPLAIN statement and is not supported. Only SELECT queries can be explained.
+*/
+func (qb *QueryBuilder) Explain() *QueryBuilder {
+	if qb.err != nil {
+		return qb
+	}
+	if qb.op != "SELECT" {
+		qb.err = fmt.Errorf("Explain() can only be used with SELECT queries")
+		return qb
+	}
+	if !qb.dbType.Supports(FeatureExplain) {
+		qb.err = fmt.Errorf("Explain() %w: %s", ErrUnsupported, qb.dbType)
+		return qb
+	}
+	if qb.dbType == Sqlite {
+		qb.explain = "EXPLAIN QUERY PLAN "
+	} else {
+		qb.explain = "EXPLAIN "
+	}
+	return qb
+}
+
+/*
+ExplainAnalyze
+
+@ Return: *QueryBuilder whose Build output is prefixed with EXPLAIN ANALYZE
+
+The query is executed when the plan is requested. MariaDB uses its ANALYZE statement and Sqlite is not supported.
+Only SELECT queries are accepted, since analyzing INSERT, UPDATE or DELETE would run the write.
+*/
+func (qb *QueryBuilder) ExplainAnalyze() *QueryBuilder {
+	if qb.err != nil {
+		return qb
+	}
+	if qb.op != "SELECT" {
+		qb.err = fmt.Errorf("ExplainAnalyze() can only be used with SELECT queries")
+		return qb
+	}
+	switch qb.dbType {
+	case PostgreSQL, Mysql:
+		qb.explain = "EXPLAIN ANALYZE "
+	case MariaDB:
+		qb.explain = "ANALYZE "
+	default:
+		qb.err = fmt.Errorf("ExplainAnalyze() %w: %s", ErrUnsupported, qb.dbType)
+	}
+	return qb
+}
+
+/*
+When
+
+@ cond: Whether fn is applied
+@ fn: Function adding clauses to the builder
+@ Return: *QueryBuilder returned by fn, or the builder itself when cond is false
+*/
+func (qb *QueryBuilder) When(cond bool, fn func(*QueryBuilder) *QueryBuilder) *QueryBuilder {
+	if qb.err != nil || !cond {
+		return qb
+	}
+	return fn(qb)
+}
+
+/*
+Unless
+
+@ cond: Whether fn is skipped
+@ fn: Function adding clauses to the builder
+@ Return: *QueryBuilder returned by fn, or the builder itself when cond is true
+*/
+func (qb *QueryBuilder) Unless(cond bool, fn func(*QueryBuilder) *QueryBuilder) *QueryBuilder {
+	return qb.When(!cond, fn)
+}
+
+/*
+ApplyEach
+
+@ qb: Builder the items are applied to
+@ items: Items applied in order, such as a list of filters
+@ fn: Function adding the clauses of one item
+@ Return: *QueryBuilder after the last item, stopping early once the builder holds an error
+
+Go methods cannot take type parameters, so ApplyEach is a function rather than a QueryBuilder method.
+*/
+func ApplyEach[T any](qb *QueryBuilder, items []T, fn func(*QueryBuilder, T) *QueryBuilder) *QueryBuilder {
+	for _, item := range items {
+		if qb.err != nil {
+			break
+		}
+		qb = fn(qb, item)
+	}
+	return qb
+}
+
+/*
+Build
+
+@ Return: Final query string, arguments slice, and error if any
+*/
+func (qb *QueryBuilder) Build() (string, []interface{}, error) {
+	if qb.err != nil {
+		return "", nil, qb.err
+	}
+	query, args, err := qb.buildWith(qb.dialect)
+	if err != nil || qb.explain == "" {
+		return query, args, err
+	}
+	return qb.explain + query, args, nil
+}
+
+// Query is a built SQL statement together with its arguments.
+type Query struct {
+	SQL  string        // SQL with dialect placeholders
+	Args []interface{} // Arguments in placeholder order
+}
+
+// String returns the SQL of the query.
+func (q Query) String() string {
+	return q.SQL
+}
+
+/*
+BuildQuery
+
+@ Return: Built query and error if any
+
+Same as Build, with the SQL and arguments kept together in a Query.
+*/
+func (qb *QueryBuilder) BuildQuery() (Query, error) {
+	query, args, err := qb.Build()
+	if err != nil {
+		return Query{}, err
+	}
+	return Query{SQL: query, Args: args}, nil
+}
+
+/*
+MustBuild
+
+@ Return: Built query and its arguments
+
+Same as Build but panics when building fails. Meant for queries built once at initialization,
+such as package-level statements, where an error is a programming bug. Do not use it on request paths.
+*/
+func (qb *QueryBuilder) MustBuild() (string, []interface{}) {
+	query, args, err := qb.Build()
+	if err != nil {
+		panic(fmt.Errorf("MustBuild() error: %w", err))
+	}
+	return query, args
+}
+
+// String returns the query built by Build without its arguments, or the error text when building fails.
+func (qb *QueryBuilder) String() string {
+	query, _, err := qb.Build()
+	if err != nil {
+		return "error: " + err.Error()
+	}
+	return query
+}
+
+// buildWith renders the query with the given dialect's placeholders.
+func (qb *QueryBuilder) buildWith(dialect Dialect) (string, []interface{}, error) {
+	switch qb.op {
+	case "SELECT":
+		return qb.buildSelect(dialect)
+	case "INSERT":
+		return qb.buildInsert(dialect)
+	case "UPDATE":
+		return qb.buildUpdate(dialect)
+	case "DELETE":
+		return qb.buildDelete(dialect)
+	default:
+		return "", nil, fmt.Errorf("unsupported operation: %s", qb.op)
+	}
+}
+
+// buildRaw renders the query with "?" placeholders so it can be embedded in another builder.
+func (qb *QueryBuilder) buildRaw() (string, []interface{}, error) {
+	if qb.err != nil {
+		return "", nil, qb.err
+	}
+	return qb.buildWith(rawDialect{qb.dialect})
+}
+
+// rawDialect keeps "?" placeholders while delegating everything else.
+type rawDialect struct {
+	Dialect
+}
+
+func (rawDialect) Placeholder(int) string {
+	return "?"
+}
+
+// queryWriter accumulates SQL text and its arguments, numbering placeholders in textual order.
+type queryWriter struct {
+	dialect Dialect
+	sql     strings.Builder
+	args    []interface{}  // Allocated per build, never shared with the builder
+	named   map[string]int // Argument index of each bound NamedArg
+}
+
+func (w *queryWriter) WriteString(s string) {
+	w.sql.WriteString(s)
+}
+
+// value returns a Raw expression literally and binds any other value.
+func (w *queryWriter) value(val interface{}) string {
+	if raw, ok := val.(Raw); ok {
+		return string(raw)
+	}
+	return w.bind(val)
+}
+
+// bind appends an argument and returns its placeholder.
+func (w *queryWriter) bind(arg interface{}) string {
+	w.args = append(w.args, arg)
+	return w.dialect.Placeholder(len(w.args))
+}
+
+func (w *queryWriter) writeClause(c sqlClause) {
+	if hasNamedArgs(c.args) {
+		w.writeNamedClause(c)
+		return
+	}
+	writePlaceholders(&w.sql, w.dialect, c.sql, len(w.args)+1)
+	w.args = append(w.args, c.args...)
+}
+
+// writeList writes items separated by sep without joining them first.
+func (w *queryWriter) writeList(items []string, sep string) {
+	for i, item := range items {
+		if i > 0 {
+			w.sql.WriteString(sep)
+		}
+		w.sql.WriteString(item)
+	}
+}
+
+func (w *queryWriter) writeClauses(clauses []sqlClause, sep string) {
+	for i, c := range clauses {
+		if i > 0 {
+			w.sql.WriteString(sep)
+		}
+		w.writeClause(c)
+	}
+}
+
+func (w *queryWriter) String() string {
+	return w.sql.String()
+}
+
+/*
+build select query string
+*/
+func (qb *QueryBuilder) buildSelect(dialect Dialect) (string, []interface{}, error) {
+	if err := qb.checkPaging(); err != nil {
+		return "", nil, err
+	}
+	conditions := qb.whereConditions()
+
+	w := &queryWriter{dialect: dialect}
+	w.sql.Grow(qb.estimateSelectSize(conditions))
+	w.args = make([]interface{}, 0, qb.estimateSelectArgs(conditions))
+
+	w.writeCTEs(qb.ctes, qb.recursive)
+	w.WriteString("SELECT ")
+	if qb.distinct {
+		w.WriteString("DISTINCT ")
+	}
+	if qb.top > 0 {
+		w.WriteString("TOP ")
+		w.WriteString(strconv.FormatInt(qb.top, 10))
+		w.WriteString(" ")
+	}
+
+	if len(qb.args) > 0 {
+		w.writeClause(sqlClause{sql: strings.Join(qb.columns, ", "), args: qb.args})
+	} else {
+		w.writeList(qb.columns, ", ")
+	}
+	w.WriteString(" FROM ")
+	if qb.from != nil {
+		w.writeClause(*qb.from)
+	} else {
+		w.WriteString(qb.table)
+	}
+
+	if len(qb.joins) > 0 {
+		w.WriteString(" ")
+		w.writeClauses(qb.joins, " ")
+	}
+
+	if len(conditions) > 0 {
+		w.WriteString(" WHERE ")
+		w.writeClauses(conditions, " AND ")
+	}
+
+	if len(qb.groupBy) > 0 {
+		w.WriteString(" GROUP BY ")
+		w.writeList(qb.groupBy, ", ")
+	}
+
+	if len(qb.having) > 0 {
+		w.WriteString(" HAVING ")
+		w.writeClauses(qb.having, " AND ")
+	}
+
+	if qb.orderBy != "" {
+		w.WriteString(" ORDER BY ")
+		w.WriteString(qb.orderBy)
+	}
+
+	var limitMarker, offsetMarker string
+	if qb.limit > 0 {
+		limitMarker = pagingLimitMarker
+	}
+	if qb.offset > 0 {
+		offsetMarker = pagingOffsetMarker
+	}
+	if paging := dialect.LimitOffset(limitMarker, offsetMarker); paging != "" {
+		w.WriteString(" ")
+		qb.writePaging(w, paging)
+	}
+
+	return w.String(), w.args, nil
+}
+
+// Markers handed to Dialect.LimitOffset so the values are bound in the order the dialect writes them.
+const (
+	pagingLimitMarker  = "\x00limit\x00"
+	pagingOffsetMarker = "\x00offset\x00"
+)
+
+// writePaging writes the paging clause, binding limit and offset where their markers appear.
+// SQLServer names the offset before the limit, so binding in a fixed order would swap positional arguments.
+func (qb *QueryBuilder) writePaging(w *queryWriter, paging string) {
+	for {
+		i := strings.IndexByte(paging, 0)
+		if i < 0 {
+			w.WriteString(paging)
+			return
+		}
+		w.WriteString(paging[:i])
+		paging = paging[i:]
+
+		switch {
+		case strings.HasPrefix(paging, pagingLimitMarker):
+			w.WriteString(w.bind(qb.limit))
+			paging = paging[len(pagingLimitMarker):]
+		case strings.HasPrefix(paging, pagingOffsetMarker):
+			w.WriteString(w.bind(qb.offset))
+			paging = paging[len(pagingOffsetMarker):]
+		default:
+			w.WriteString(paging[:1])
+			paging = paging[1:]
+		}
+	}
+}
+
+// estimateSelectSize approximates the length of the SELECT statement so the buffer grows once.
+func (qb *QueryBuilder) estimateSelectSize(conditions []sqlClause) int {
+	// Keywords, separators and placeholder expansion
+	size := 64 + len(qb.table) + len(qb.orderBy)
+	if qb.from != nil {
+		size += len(qb.from.sql)
+	}
+	for _, list := range [][]string{qb.columns, qb.groupBy} {
+		for _, item := range list {
+			size += len(item) + 2
+		}
+	}
+	for _, clauses := range [][]sqlClause{qb.ctes, qb.joins, conditions, qb.having} {
+		for _, c := range clauses {
+			size += len(c.sql) + 5 + 2*len(c.args)
+		}
+	}
+	return size
+}
+
+// estimateSelectArgs counts the arguments of the SELECT statement including LIMIT and OFFSET.
+func (qb *QueryBuilder) estimateSelectArgs(conditions []sqlClause) int {
+	count := len(qb.args) + 2
+	if qb.from != nil {
+		count += len(qb.from.args)
+	}
+	for _, clauses := range [][]sqlClause{qb.ctes, qb.joins, conditions, qb.having} {
+		for _, c := range clauses {
+			count += len(c.args)
+		}
+	}
+	return count
+}
+
+/*
+build insert query string
+*/
+func (qb *QueryBuilder) buildInsert(dialect Dialect) (string, []interface{}, error) {
+	if qb.data == nil && qb.rows == nil && !qb.defaults {
+		return "", nil, fmt.Errorf("no data provided for INSERT")
+	}
+	w := &queryWriter{dialect: dialect}
+
+	if qb.data == nil && qb.rows == nil {
+		if qb.dbType == MariaDB || qb.dbType == Mysql {
+			w.WriteString(qb.insertVerb() + " " + qb.table + " () VALUES ()")
+		} else {
+			w.WriteString(qb.insertVerb() + " " + qb.table + " DEFAULT VALUES")
+		}
+	} else if qb.rows != nil {
+		w.args = make([]interface{}, 0, len(qb.rows)*len(qb.rowColumns))
+		w.WriteString(qb.insertVerb() + " " + qb.table + " (" + strings.Join(qb.rowColumns, ", ") + ") VALUES ")
+		for i, row := range qb.rows {
+			if i > 0 {
+				w.WriteString(", ")
+			}
+			w.WriteString("(")
+			for j, val := range row {
+				if j > 0 {
+					w.WriteString(", ")
+				}
+				w.WriteString(w.value(val))
+			}
+			w.WriteString(")")
+		}
+	} else {
+		var cols []string
+		var placeholders []string
+
+		for _, col := range sortedKeys(qb.data) {
+			safeCol, err := dialect.EscapeIdentifier(col)
+			if err != nil {
+				return "", nil, err
+			}
+			cols = append(cols, safeCol)
+			placeholders = append(placeholders, w.value(qb.data[col]))
+		}
+
+		w.WriteString(fmt.Sprintf("%s %s (%s) VALUES (%s)", qb.insertVerb(), qb.table, strings.Join(cols, ", "), strings.Join(placeholders, ", ")))
+	}
+
+	if qb.conflict != nil {
+		w.writeConflict(qb.conflict)
+	} else if qb.ignore && qb.dbType.Supports(FeatureOnConflict) {
+		w.WriteString(" ON CONFLICT DO NOTHING")
+	}
+	if qb.returning != "" && qb.dbType.Supports(FeatureReturning) {
+		w.WriteString(" RETURNING " + qb.returning)
+	}
+
+	return w.String(), w.args, nil
+}
+
+/*
+build update query string
+*/
+func (qb *QueryBuilder) buildUpdate(dialect Dialect) (string, []interface{}, error) {
+	if qb.data == nil && len(qb.sets) == 0 {
+		return "", nil, fmt.Errorf("no data provided for UPDATE")
+	}
+	w := &queryWriter{dialect: dialect}
+	w.WriteString("UPDATE " + qb.table + " SET ")
+
+	for i, col := range sortedKeys(qb.data) {
+		safeCol, err := dialect.EscapeIdentifier(col)
+		if err != nil {
+			return "", nil, err
+		}
+		if i > 0 {
+			w.WriteString(", ")
+		}
+		w.WriteString(safeCol + " = " + w.value(qb.data[col]))
+	}
+
+	for i, set := range qb.sets {
+		if i > 0 || len(qb.data) > 0 {
+			w.WriteString(", ")
+		}
+		w.writeClause(set)
+	}
+
+	if qb.lockColumn != "" {
+		w.WriteString(fmt.Sprintf(", %s = %s + 1", qb.lockColumn, qb.lockColumn))
+	}
+
+	conditions := qb.whereConditions()
+	if qb.lockColumn != "" {
+		lockCondition := sqlClause{sql: qb.lockColumn + " = ?", args: []interface{}{qb.lockVersion}}
+		conditions = append(conditions[:len(conditions):len(conditions)], lockCondition)
+	}
+
+	if len(conditions) > 0 {
+		w.WriteString(" WHERE ")
+		w.writeClauses(conditions, " AND ")
+	}
+
+	return w.String(), w.args, nil
+}
+
+/*
+build delete query string
+*/
+func (qb *QueryBuilder) buildDelete(dialect Dialect) (string, []interface{}, error) {
+	w := &queryWriter{dialect: dialect}
+	if qb.softDeleteColumn != "" {
+		w.WriteString("UPDATE ")
+		w.WriteString(qb.table)
+		w.WriteString(" SET " + qb.softDeleteColumn + " = CURRENT_TIMESTAMP")
+	} else {
+		w.WriteString("DELETE FROM ")
+		w.WriteString(qb.table)
+	}
+	if conditions := qb.whereConditions(); len(conditions) > 0 {
+		w.WriteString(" WHERE ")
+		w.writeClauses(conditions, " AND ")
+	}
+	return w.String(), w.args, nil
+}
+
+// whereConditions returns the WHERE conditions including the implicit soft-delete filter.
+func (qb *QueryBuilder) whereConditions() []sqlClause {
+	if qb.softDeleteColumn == "" || qb.withTrashed {
+		return qb.conditions
+	}
+	conditions := make([]sqlClause, 0, len(qb.conditions)+1)
+	conditions = append(conditions, qb.conditions...)
+	return append(conditions, sqlClause{sql: qb.softDeleteQualified() + " IS NULL"})
+}
+
+// softDeleteQualified qualifies the soft-delete column with the table alias, or the table name without one,
+// so the filter stays unambiguous once other tables are joined.
+func (qb *QueryBuilder) softDeleteQualified() string {
+	if strings.Contains(qb.softDeleteColumn, ".") {
+		return qb.softDeleteColumn
+	}
+	fields := strings.Fields(qb.table)
+	return fields[len(fields)-1] + "." + qb.softDeleteColumn
+}
+
+func (qb *QueryBuilder) AddClause(clause *[]string, format string, values ...interface{}) *QueryBuilder {
+	if qb.err != nil {
+		return qb
+	}
+	*clause = append(*clause, fmt.Sprintf(format, values...))
+	return qb
+}
+
+func (qb *QueryBuilder) AddSafeClause(clause *[]string, format string, identifier string, extra string) *QueryBuilder {
+	if qb.err != nil {
+		return qb
+	}
+	safe, err := qb.dialect.EscapeIdentifier(identifier)
+	if err != nil {
+		qb.err = err
+		return qb
+	}
+	*clause = append(*clause, fmt.Sprintf(format, safe, extra))
+	return qb
+}
+
+/*
+JoinSub
+
+@ kind: Join type (INNER, LEFT, RIGHT, FULL)
+@ sub: SELECT builder joined as a derived table
+@ alias: Alias of the derived table
+@ onCondition: Join condition
+@ Return: *QueryBuilder with "kind JOIN (sub) AS alias ON condition" added
+*/
+func (qb *QueryBuilder) JoinSub(kind string, sub *QueryBuilder, alias, onCondition string) *QueryBuilder {
+	return qb.joinSub("JoinSub", kind, "", sub, alias, onCondition)
+}
+
+/*
+LateralJoin
+
+@ kind: Join type (INNER, LEFT, RIGHT, FULL)
+@ sub: SELECT builder that may reference columns of the preceding tables
+@ alias: Alias of the derived table
+@ onCondition: Join condition (e.g. "true" for top-N-per-group queries)
+@ Return: *QueryBuilder with "kind JOIN LATERAL (sub) AS alias ON condition" added
+*/
+func (qb *QueryBuilder) LateralJoin(kind string, sub *QueryBuilder, alias, onCondition string) *QueryBuilder {
+	if qb.err == nil && !qb.dbType.Supports(FeatureLateral) {
+		qb.err = fmt.Errorf("LateralJoin() %w: %s", ErrUnsupported, qb.dbType)
+		return qb
+	}
+	return qb.joinSub("LateralJoin", kind, "LATERAL ", sub, alias, onCondition)
+}
+
+func (qb *QueryBuilder) joinSub(method, kind, modifier string, sub *QueryBuilder, alias, onCondition string) *QueryBuilder {
+	if qb.err != nil {
+		return qb
+	}
+	if qb.op != "SELECT" {
+		qb.err = fmt.Errorf("%s() can only be used with SELECT queries", method)
+		return qb
+	}
+	joinKind, ok := joinKinds[strings.ToUpper(strings.TrimSpace(kind))]
+	if !ok {
+		qb.err = fmt.Errorf("%s() unsupported join type: %s", method, kind)
+		return qb
+	}
+	if strings.TrimSpace(onCondition) == "" {
+		qb.err = fmt.Errorf("%s() join condition cannot be empty", method)
+		return qb
+	}
+	derived, err := derivedTable(qb.dialect, sub, alias)
+	if err != nil {
+		qb.err = err
+		return qb
+	}
+	qb.joins = append(qb.joins, sqlClause{
+		sql:  joinKind + " JOIN " + modifier + derived.sql + " ON " + onCondition,
+		args: derived.args,
+	})
+	return qb
+}
+
+// joinKinds maps accepted join types to their SQL keywords.
+var joinKinds = map[string]string{
+	"INNER":       "INNER",
+	"LEFT":        "LEFT",
+	"LEFT OUTER":  "LEFT",
+	"RIGHT":       "RIGHT",
+	"RIGHT OUTER": "RIGHT",
+	"FULL":        "FULL",
+	"FULL OUTER":  "FULL",
+}
+
+// OrderSpec is a column and direction of an ORDER BY list.
+// An empty Direction leaves the database default (ASC), and an empty Nulls the database default NULL placement.
+type OrderSpec struct {
+	Column    string
+	Direction string
+	Nulls     NullsOrder
+}
+
+// NullsOrder places NULL values first or last in an ORDER BY list.
+type NullsOrder string
+
+const (
+	NullsFirst NullsOrder = "FIRST" // NULLS FIRST
+	NullsLast  NullsOrder = "LAST"  // NULLS LAST
+)
+
+// orderTerm renders one ORDER BY entry.
+// Databases without FeatureNullsOrder sort on "column IS NULL" first to emulate NULLS FIRST/LAST.
+func (qb *QueryBuilder) orderTerm(spec OrderSpec) (string, error) {
+	if !qb.checkColumns(spec.Column) {
+		return "", qb.err
+	}
+	safeCol, err := qb.dialect.EscapeIdentifier(spec.Column)
+	if err != nil {
+		return "", err
+	}
+	term := safeCol
+	if spec.Direction != "" {
+		term += " " + ValidateDirection(spec.Direction)
+	}
+
+	switch spec.Nulls {
+	case "":
+		return term, nil
+	case NullsFirst, NullsLast:
+	default:
+		return "", fmt.Errorf("invalid nulls order: %s", spec.Nulls)
+	}
+	if qb.dbType.Supports(FeatureNullsOrder) {
+		return term + " NULLS " + string(spec.Nulls), nil
+	}
+	if qb.dbType == SQLServer {
+		// T-SQL cannot sort on a predicate, so the NULL test is wrapped in CASE
+		if spec.Nulls == NullsFirst {
+			return "CASE WHEN " + safeCol + " IS NULL THEN 0 ELSE 1 END, " + term, nil
+		}
+		return "CASE WHEN " + safeCol + " IS NULL THEN 1 ELSE 0 END, " + term, nil
+	}
+	if spec.Nulls == NullsFirst {
+		return safeCol + " IS NULL DESC, " + term, nil
+	}
+	return safeCol + " IS NULL, " + term, nil
 }
 
-// Set adds data for UPDATE operations.
-// Data should be a map of column names to values.
-func (qb *QueryBuilder) Set(data map[string]interface{}) *QueryBuilder {
+/*
+OrderByMulti
+
+@ specs: Columns, directions and NULL placement of the ORDER BY list
+@ Return: *QueryBuilder with ORDER BY clause set
+*/
+func (qb *QueryBuilder) OrderByMulti(specs ...OrderSpec) *QueryBuilder {
 	if qb.err != nil {
 		return qb
 	}
-	if qb.op != "UPDATE" {
-		qb.err = fmt.Errorf("Set() can only be used with UPDATE operation")
-		return qb
-	}
-	if len(data) == 0 {
-		qb.err = fmt.Errorf("Set() requires at least one column-value pair")
-		return qb
+	terms := make([]string, len(specs))
+	for i, spec := range specs {
+		term, err := qb.orderTerm(spec)
+		if err != nil {
+			qb.err = err
+			return qb
+		}
+		terms[i] = term
 	}
-	qb.data = data
+	qb.orderBy = strings.Join(terms, ", ")
 	return qb
 }
 
 /*
-Returning
+Over
 
-@ clause: RETURNING clause string (for PostgreSQL)
-@ Return: *QueryBuilder with RETURNING clause set
+@ partitionBy: PARTITION BY columns
+@ orderBy: ORDER BY columns of the window
+@ Return: Escaped "OVER (PARTITION BY ... ORDER BY ...)" text for use with SelectRaw
 */
-func (qb *QueryBuilder) Returning(clause string) *QueryBuilder {
-	if qb.op != "INSERT" {
-		qb.err = fmt.Errorf("Returning() can only be used with INSERT operation")
-		return qb
+func (qb *QueryBuilder) Over(partitionBy []string, orderBy []OrderSpec) string {
+	if qb.err != nil {
+		return ""
 	}
-	qb.returning = clause
-	return qb
+
+	var parts []string
+	if len(partitionBy) > 0 {
+		safeColumns := sanitizeColumns(qb.dialect, partitionBy, &qb.err)
+		if qb.err != nil {
+			return ""
+		}
+		parts = append(parts, "PARTITION BY "+strings.Join(safeColumns, ", "))
+	}
+	if len(orderBy) > 0 {
+		orders := make([]string, len(orderBy))
+		for i, spec := range orderBy {
+			term, err := qb.orderTerm(spec)
+			if err != nil {
+				qb.err = err
+				return ""
+			}
+			orders[i] = term
+		}
+		parts = append(parts, "ORDER BY "+strings.Join(orders, ", "))
+	}
+	return "OVER (" + strings.Join(parts, " ") + ")"
 }
 
 /*
-Build
+Window
 
-@ Return: Final query string, arguments slice, and error if any
+@ function: Window or aggregate function call (e.g. "SUM(amount)", "ROW_NUMBER()")
+@ partitionBy: PARTITION BY columns
+@ orderBy: ORDER BY columns of the window
+@ Return: "function OVER (...)" text for use with SelectRaw
 */
-func (qb *QueryBuilder) Build() (string, []interface{}, error) {
-	if qb.err != nil {
-		return "", nil, qb.err
+func (qb *QueryBuilder) Window(function string, partitionBy []string, orderBy []OrderSpec) string {
+	if qb.err == nil && strings.TrimSpace(function) == "" {
+		qb.err = fmt.Errorf("window function cannot be empty")
 	}
-	switch qb.op {
-	case "SELECT":
-		return qb.buildSelect()
-	case "INSERT":
-		return qb.buildInsert()
-	case "UPDATE":
-		return qb.buildUpdate()
-	case "DELETE":
-		return qb.buildDelete()
-	default:
-		return "", nil, fmt.Errorf("unsupported operation: %s", qb.op)
+	over := qb.Over(partitionBy, orderBy)
+	if qb.err != nil {
+		return ""
 	}
+	return function + " " + over
 }
 
 /*
-build select query string
+SelectRowNumber
+
+@ alias: Alias of the numbered column
+@ partitionBy: PARTITION BY columns
+@ orderBy: ORDER BY columns of the window
+@ Return: *QueryBuilder selecting "ROW_NUMBER() OVER (...) AS alias"
 */
-func (qb *QueryBuilder) buildSelect() (string, []interface{}, error) {
-	var queryBuilder strings.Builder
-	args := make([]interface{}, len(qb.args))
-	copy(args, qb.args) // Create a copy to avoid modifying the original
+func (qb *QueryBuilder) SelectRowNumber(alias string, partitionBy []string, orderBy []OrderSpec) *QueryBuilder {
+	return qb.selectWindow("ROW_NUMBER()", alias, partitionBy, orderBy)
+}
 
-	queryBuilder.WriteString("SELECT ")
-	if qb.distinct {
-		queryBuilder.WriteString("DISTINCT ")
-	}
+/*
+SelectRank
 
-	queryBuilder.WriteString(strings.Join(qb.columns, ", "))
-	queryBuilder.WriteString(" FROM ")
-	queryBuilder.WriteString(qb.table)
+@ alias: Alias of the rank column
+@ partitionBy: PARTITION BY columns
+@ orderBy: ORDER BY columns of the window
+@ Return: *QueryBuilder selecting "RANK() OVER (...) AS alias"
+*/
+func (qb *QueryBuilder) SelectRank(alias string, partitionBy []string, orderBy []OrderSpec) *QueryBuilder {
+	return qb.selectWindow("RANK()", alias, partitionBy, orderBy)
+}
 
-	if len(qb.joins) > 0 {
-		queryBuilder.WriteString(" " + strings.Join(qb.joins, " "))
-	}
+/*
+SelectDenseRank
 
-	if len(qb.conditions) > 0 {
-		queryBuilder.WriteString(" WHERE " + strings.Join(qb.conditions, " AND "))
-	}
+@ alias: Alias of the rank column
+@ partitionBy: PARTITION BY columns
+@ orderBy: ORDER BY columns of the window
+@ Return: *QueryBuilder selecting "DENSE_RANK() OVER (...) AS alias"
+*/
+func (qb *QueryBuilder) SelectDenseRank(alias string, partitionBy []string, orderBy []OrderSpec) *QueryBuilder {
+	return qb.selectWindow("DENSE_RANK()", alias, partitionBy, orderBy)
+}
 
-	if len(qb.groupBy) > 0 {
-		queryBuilder.WriteString(" GROUP BY " + strings.Join(qb.groupBy, ", "))
+func (qb *QueryBuilder) selectWindow(function, alias string, partitionBy []string, orderBy []OrderSpec) *QueryBuilder {
+	if qb.err != nil {
+		return qb
 	}
-
-	if len(qb.having) > 0 {
-		queryBuilder.WriteString(" HAVING " + strings.Join(qb.having, " AND "))
+	window := qb.Window(function, partitionBy, orderBy)
+	if qb.err != nil {
+		return qb
 	}
+	return qb.selectAs(window, alias)
+}
 
-	if qb.orderBy != "" {
-		queryBuilder.WriteString(" ORDER BY " + qb.orderBy)
+// selectAs adds a generated expression to the SELECT list under an escaped column alias.
+func (qb *QueryBuilder) selectAs(expr, alias string, args ...interface{}) *QueryBuilder {
+	if qb.err != nil {
+		return qb
 	}
-
-	if qb.limit > 0 {
-		nextParamIndex := len(args) + 1
-		if qb.dbType == PostgreSQL {
-			queryBuilder.WriteString(fmt.Sprintf(" LIMIT $%d", nextParamIndex))
-		} else {
-			queryBuilder.WriteString(" LIMIT ?")
-		}
-		args = append(args, qb.limit)
+	if strings.Contains(alias, ".") {
+		qb.err = fmt.Errorf("%w: alias %q", ErrInvalidIdentifier, alias)
+		return qb
 	}
-
-	if qb.offset > 0 {
-		nextParamIndex := len(args) + 1
-		if qb.dbType == PostgreSQL {
-			queryBuilder.WriteString(fmt.Sprintf(" OFFSET $%d", nextParamIndex))
-		} else {
-			queryBuilder.WriteString(" OFFSET ?")
-		}
-		args = append(args, qb.offset)
+	safeAlias, err := qb.dialect.EscapeIdentifier(alias)
+	if err != nil {
+		qb.err = fmt.Errorf("invalid column alias: %w", err)
+		return qb
 	}
-
-	return queryBuilder.String(), args, nil
+	return qb.SelectRaw(expr+" AS "+safeAlias, args...)
 }
 
 /*
-build insert query string
-*/
-func (qb *QueryBuilder) buildInsert() (string, []interface{}, error) {
-	if qb.data == nil {
-		return "", nil, fmt.Errorf("no data provided for INSERT")
-	}
-	var cols []string
-	var placeholders []string
-	var args []interface{}
-
-	i := 1
-	for col, val := range qb.data {
-		safeCol, err := EscapeIdentifier(qb.dbType, col)
-		if err != nil {
-			return "", nil, err
-		}
-		cols = append(cols, safeCol)
+SelectGroupConcat
 
-		if qb.dbType == PostgreSQL {
-			placeholders = append(placeholders, fmt.Sprintf("$%d", i))
-		} else {
-			placeholders = append(placeholders, "?")
-		}
+@ column: Column whose values are concatenated
+@ separator: Text placed between the values
+@ alias: Alias of the concatenated column
+@ Return: *QueryBuilder with the string aggregation selected
 
-		args = append(args, val)
-		i++
+Builds STRING_AGG(column, ?) for PostgreSQL and SQLServer, GROUP_CONCAT(column, ?) for Sqlite
+and GROUP_CONCAT(column SEPARATOR 'separator') for MariaDB and Mysql, whose SEPARATOR only accepts a literal.
+*/
+func (qb *QueryBuilder) SelectGroupConcat(column, separator, alias string) *QueryBuilder {
+	if qb.err != nil {
+		return qb
 	}
-
-	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", qb.table, strings.Join(cols, ", "), strings.Join(placeholders, ", "))
-	if qb.dbType == PostgreSQL && qb.returning != "" {
-		query += " RETURNING " + qb.returning
+	safeCol, err := qb.dialect.EscapeIdentifier(column)
+	if err != nil {
+		qb.err = err
+		return qb
 	}
 
-	return query, args, nil
+	switch qb.dbType {
+	case PostgreSQL, SQLServer:
+		return qb.selectAs("STRING_AGG("+safeCol+", ?)", alias, separator)
+	case MariaDB, Mysql:
+		if strings.ContainsAny(separator, `'\`) {
+			qb.err = fmt.Errorf("SelectGroupConcat() separator cannot contain quotes or backslashes: %q", separator)
+			return qb
+		}
+		return qb.selectAs("GROUP_CONCAT("+safeCol+" SEPARATOR '"+separator+"')", alias)
+	default:
+		return qb.selectAs("GROUP_CONCAT("+safeCol+", ?)", alias, separator)
+	}
 }
 
 /*
-build update query string
+WithTotalCount
+
+@ alias: Alias of the total count column
+@ Return: *QueryBuilder with COUNT(*) OVER() AS alias selected
+
+Every returned row carries the number of rows matching the query before LIMIT and OFFSET,
+so a page and its total come back in one query. An empty page returns no total.
 */
-func (qb *QueryBuilder) buildUpdate() (string, []interface{}, error) {
-	if qb.data == nil {
-		return "", nil, fmt.Errorf("no data provided for UPDATE")
+func (qb *QueryBuilder) WithTotalCount(alias string) *QueryBuilder {
+	if qb.err != nil {
+		return qb
+	}
+	if !qb.dbType.Supports(FeatureWindowFunctions) {
+		qb.err = fmt.Errorf("WithTotalCount() %w: %s", ErrUnsupported, qb.dbType)
+		return qb
 	}
-	var setClauses []string
-	var updateArgs []interface{}
+	return qb.selectAs("COUNT(*) OVER()", alias)
+}
 
-	i := 1
-	for col, val := range qb.data {
-		safeCol, err := EscapeIdentifier(qb.dbType, col)
-		if err != nil {
-			return "", nil, err
-		}
+/*
+SelectCoalesce
 
-		if qb.dbType == PostgreSQL {
-			setClauses = append(setClauses, fmt.Sprintf("%s = $%d", safeCol, i))
-		} else {
-			setClauses = append(setClauses, fmt.Sprintf("%s = ?", safeCol))
-		}
+@ alias: Alias of the selected expression
+@ exprs: Column names, Raw literals or bound values tried in order
+@ Return: *QueryBuilder with COALESCE(exprs...) AS alias selected
 
-		updateArgs = append(updateArgs, val)
-		i++
+Strings always name columns, so text fallbacks are written as Raw("'text'").
+*/
+func (qb *QueryBuilder) SelectCoalesce(alias string, exprs ...interface{}) *QueryBuilder {
+	if qb.err != nil {
+		return qb
+	}
+	if len(exprs) < 2 {
+		qb.err = fmt.Errorf("SelectCoalesce() requires at least two expressions")
+		return qb
 	}
+	terms, args, err := qb.functionArgs(exprs)
+	if err != nil {
+		qb.err = err
+		return qb
+	}
+	return qb.selectAs("COALESCE("+strings.Join(terms, ", ")+")", alias, args...)
+}
 
-	query := fmt.Sprintf("UPDATE %s SET %s", qb.table, strings.Join(setClauses, ", "))
+/*
+SelectNullIf
 
-	if len(qb.conditions) > 0 {
-		query += " WHERE " + strings.Join(qb.conditions, " AND ")
+@ alias: Alias of the selected expression
+@ a: Column name, Raw literal or bound value returned unless it equals b
+@ b: Column name, Raw literal or bound value compared against a
+@ Return: *QueryBuilder with NULLIF(a, b) AS alias selected
+*/
+func (qb *QueryBuilder) SelectNullIf(alias string, a, b interface{}) *QueryBuilder {
+	if qb.err != nil {
+		return qb
+	}
+	terms, args, err := qb.functionArgs([]interface{}{a, b})
+	if err != nil {
+		qb.err = err
+		return qb
+	}
+	return qb.selectAs("NULLIF("+strings.Join(terms, ", ")+")", alias, args...)
+}
 
-		if qb.dbType == PostgreSQL {
-			for j := 0; j < len(qb.args); j++ {
-				updateArgs = append(updateArgs, qb.args[j])
+// functionArgs renders SQL function arguments: strings are escaped column names,
+// Raw values are written literally and anything else is bound as a placeholder.
+func (qb *QueryBuilder) functionArgs(exprs []interface{}) ([]string, []interface{}, error) {
+	terms := make([]string, len(exprs))
+	var args []interface{}
+	for i, expr := range exprs {
+		switch v := expr.(type) {
+		case Raw:
+			if strings.TrimSpace(string(v)) == "" {
+				return nil, nil, fmt.Errorf("raw expression cannot be empty")
 			}
-		} else {
-			updateArgs = append(updateArgs, qb.args...)
+			terms[i] = string(v)
+		case string:
+			if !qb.checkColumns(v) {
+				return nil, nil, qb.err
+			}
+			safeCol, err := qb.dialect.EscapeIdentifier(v)
+			if err != nil {
+				return nil, nil, err
+			}
+			terms[i] = safeCol
+		default:
+			terms[i] = "?"
+			args = append(args, v)
 		}
 	}
-
-	return query, updateArgs, nil
+	return terms, args, nil
 }
 
 /*
-build delete query string
+FromSubquery
+
+@ sub: SELECT builder used as the derived table
+@ alias: Alias of the derived table
+@ Return: *QueryBuilder selecting FROM (sub) AS alias
 */
-func (qb *QueryBuilder) buildDelete() (string, []interface{}, error) {
-	var queryBuilder strings.Builder
-	queryBuilder.WriteString("DELETE FROM ")
-	queryBuilder.WriteString(qb.table)
-	if len(qb.conditions) > 0 {
-		queryBuilder.WriteString(" WHERE " + strings.Join(qb.conditions, " AND "))
+func (qb *QueryBuilder) FromSubquery(sub *QueryBuilder, alias string) *QueryBuilder {
+	if qb.err != nil {
+		return qb
+	}
+	if qb.op != "SELECT" {
+		qb.err = fmt.Errorf("FromSubquery() can only be used with SELECT queries")
+		return qb
+	}
+	derived, err := derivedTable(qb.dialect, sub, alias)
+	if err != nil {
+		qb.err = err
+		return qb
 	}
-	return queryBuilder.String(), qb.args, nil
+	qb.from = &derived
+	return qb
 }
 
-func (qb *QueryBuilder) AddClause(clause *[]string, format string, values ...interface{}) *QueryBuilder {
+/*
+SelectSubquery
+
+@ sub: SELECT builder returning a single value, may reference the outer tables
+@ alias: Alias of the selected column
+@ Return: *QueryBuilder with (sub) AS alias added to the SELECT list
+
+The subquery arguments are bound before those of the WHERE clause, in SELECT list order.
+*/
+func (qb *QueryBuilder) SelectSubquery(sub *QueryBuilder, alias string) *QueryBuilder {
 	if qb.err != nil {
 		return qb
 	}
-	*clause = append(*clause, fmt.Sprintf(format, values...))
-	return qb
+	if qb.op != "SELECT" {
+		qb.err = fmt.Errorf("SelectSubquery() can only be used with SELECT queries")
+		return qb
+	}
+	column, err := derivedTable(qb.dialect, sub, alias)
+	if err != nil {
+		qb.err = err
+		return qb
+	}
+	return qb.SelectRaw(column.sql, column.args...)
 }
 
-func (qb *QueryBuilder) AddSafeClause(clause *[]string, format string, identifier string, extra string) *QueryBuilder {
+/*
+FromValues
+
+@ alias: Alias of the inline table
+@ columns: Column names of the inline table
+@ rows: Row values, bound as arguments in row-major order
+@ Return: *QueryBuilder selecting FROM an inline VALUES table
+
+PostgreSQL emits "(VALUES (...), (...)) AS alias(columns)".
+MariaDB, MySQL and SQLite emit the equivalent "(SELECT ... UNION ALL SELECT ...) AS alias".
+*/
+func (qb *QueryBuilder) FromValues(alias string, columns []string, rows [][]interface{}) *QueryBuilder {
+	if qb.err != nil {
+		return qb
+	}
+	if qb.op != "SELECT" {
+		qb.err = fmt.Errorf("FromValues() can only be used with SELECT queries")
+		return qb
+	}
+	if len(columns) == 0 || len(rows) == 0 {
+		qb.err = fmt.Errorf("FromValues() requires at least one column and one row")
+		return qb
+	}
+	safeColumns := sanitizeColumns(qb.dialect, columns, &qb.err)
 	if qb.err != nil {
 		return qb
 	}
-	safe, err := EscapeIdentifier(qb.dbType, identifier)
+	if strings.Contains(alias, ".") {
+		qb.err = fmt.Errorf("%w: alias %q", ErrInvalidIdentifier, alias)
+		return qb
+	}
+	safeAlias, err := qb.dialect.EscapeIdentifier(alias)
 	if err != nil {
-		qb.err = err
+		qb.err = fmt.Errorf("invalid values alias: %w", err)
 		return qb
 	}
-	*clause = append(*clause, fmt.Sprintf(format, safe, extra))
+
+	rowPlaceholders := questionMarks(len(columns))
+	selects := make([]string, len(rows))
+	args := make([]interface{}, 0, len(rows)*len(columns))
+	for i, row := range rows {
+		if len(row) != len(columns) {
+			qb.err = fmt.Errorf("FromValues() row %d has %d values, expected %d", i, len(row), len(columns))
+			return qb
+		}
+		args = append(args, row...)
+		switch {
+		case qb.dbType == PostgreSQL:
+			selects[i] = "(" + rowPlaceholders + ")"
+		case i == 0:
+			aliased := make([]string, len(safeColumns))
+			for j, col := range safeColumns {
+				aliased[j] = "? AS " + col
+			}
+			selects[i] = "SELECT " + strings.Join(aliased, ", ")
+		default:
+			selects[i] = "SELECT " + rowPlaceholders
+		}
+	}
+
+	var values string
+	if qb.dbType == PostgreSQL {
+		values = "(VALUES " + strings.Join(selects, ", ") + ") AS " + safeAlias + "(" + strings.Join(safeColumns, ", ") + ")"
+	} else {
+		values = "(" + strings.Join(selects, " UNION ALL ") + ") AS " + safeAlias
+	}
+	qb.from = &sqlClause{sql: values, args: args}
 	return qb
 }
 
-// Support for subqueries
+// derivedTable renders a SELECT builder as "(sub) AS alias" with "?" placeholders.
+func derivedTable(dialect Dialect, sub *QueryBuilder, alias string) (sqlClause, error) {
+	if sub == nil {
+		return sqlClause{}, fmt.Errorf("subquery cannot be nil")
+	}
+	if sub.err == nil && sub.op != "SELECT" {
+		return sqlClause{}, fmt.Errorf("subquery must be a SELECT query")
+	}
+	if strings.Contains(alias, ".") {
+		return sqlClause{}, fmt.Errorf("%w: alias %q", ErrInvalidIdentifier, alias)
+	}
+	safeAlias, err := dialect.EscapeIdentifier(alias)
+	if err != nil {
+		return sqlClause{}, fmt.Errorf("invalid subquery alias: %w", err)
+	}
+	subSql, subArgs, err := sub.buildRaw()
+	if err != nil {
+		return sqlClause{}, fmt.Errorf("subquery error: %w", err)
+	}
+	return sqlClause{sql: "(" + subSql + ") AS " + safeAlias, args: subArgs}, nil
+}
+
+// Subquery renders a builder as "(subquery) AS alias" for use with SelectRaw.
+// The subquery arguments are added to the SELECT list arguments.
 func (qb *QueryBuilder) Subquery(subquery *QueryBuilder, alias string) string {
-	subSql, subArgs, err := subquery.Build()
+	subSql, subArgs, err := subquery.buildRaw()
 	if err != nil {
 		qb.err = err
 		return ""
@@ -777,7 +2621,7 @@ shiftPlaceholders
 // 	nextIndex := len(qb.args) + 1
 
 // 	// Replace placeholders consistently based on DB type
-// 	updatedCondition := ReplacePlaceholders(qb.dbType, condition, nextIndex)
+// 	updatedCondition := replacePlaceholders(qb.dialect, condition, nextIndex)
 
 // 	// Return the updated condition and args
 // 	return updatedCondition, args
@@ -791,46 +2635,7 @@ EscapeIdentifier
 @ Return: Escaped identifier and error if any
 */
 func EscapeIdentifier(dbType DBType, name string) (string, error) {
-	if name == "*" {
-		return name, nil
-	}
-	if name == "" {
-		return "", fmt.Errorf("empty identifier not allowed")
-	}
-
-	// 따옴표 없이 그대로 반환
-	return name, nil
-
-	// if name == "*" {
-	// 	return name, nil
-	// }
-	// if name == "" {
-	// 	return "", fmt.Errorf("empty identifier not allowed")
-	// }
-
-	// // 전체 식별자를 하나의 문자열로 처리할지 결정
-	// if strings.Contains(name, ".") {
-	// 	// 전체를 하나의 문자열로 처리하는 경우
-	// 	switch dbType {
-	// 	case PostgreSQL:
-	// 		return fmt.Sprintf("\"%s\"", strings.ReplaceAll(name, "\"", "\"\"")), nil
-	// 	case MariaDB, Mysql:
-	// 		return fmt.Sprintf("`%s`", strings.ReplaceAll(name, "`", "``")), nil
-	// 	}
-	// 	return name, nil
-	// }
-
-	// // 테이블.컬럼 형식 처리
-	// parts := strings.Split(name, ".")
-	// for i, part := range parts {
-	// 	switch dbType {
-	// 	case PostgreSQL:
-	// 		parts[i] = fmt.Sprintf("\"%s\"", strings.ReplaceAll(part, "\"", "\"\""))
-	// 	case MariaDB, Mysql:
-	// 		parts[i] = fmt.Sprintf("`%s`", strings.ReplaceAll(part, "`", "``"))
-	// 	}
-	// }
-	// return strings.Join(parts, "."), nil
+	return dialectFor(dbType).EscapeIdentifier(name)
 }
 
 /*
@@ -855,20 +2660,78 @@ ReplacePlaceholders
 @ condition: Condition string with placeholders
 @ startIdx: Starting index for placeholders
 @ Return: Condition string with replaced placeholders
+
+"?" inside quoted literals or identifiers is kept and "??" is written as a literal "?".
 */
 func ReplacePlaceholders(dbType DBType, input string, start int) string {
-	switch dbType {
-	case PostgreSQL:
-		result := input
-		count := 0
-		for strings.Contains(result, "?") {
-			count++
-			result = strings.Replace(result, "?", fmt.Sprintf("$%d", start+count-1), 1)
-		}
-		return result
-	default:
+	return replacePlaceholders(dialectFor(dbType), input, start)
+}
+
+func replacePlaceholders(dialect Dialect, input string, start int) string {
+	if !strings.Contains(input, "?") {
 		return input
 	}
+
+	var result strings.Builder
+	result.Grow(len(input) + 8)
+	writePlaceholders(&result, dialect, input, start)
+	return result.String()
+}
+
+// writePlaceholders writes input to b, numbering "?" placeholders from start in a single pass.
+func writePlaceholders(b *strings.Builder, dialect Dialect, input string, start int) {
+	index := start
+	for i := 0; i < len(input); {
+		switch input[i] {
+		case '\'', '"':
+			end := skipQuoted(input, i)
+			b.WriteString(input[i:end])
+			i = end
+		case '?':
+			if i+1 < len(input) && input[i+1] == '?' {
+				b.WriteString(escapedQuestion(dialect))
+				i += 2
+				continue
+			}
+			b.WriteString(dialect.Placeholder(index))
+			index++
+			i++
+		default:
+			next := strings.IndexAny(input[i:], "'\"?")
+			if next < 0 {
+				b.WriteString(input[i:])
+				return
+			}
+			b.WriteString(input[i : i+next])
+			i += next
+		}
+	}
+}
+
+// skipQuoted returns the index just past the quoted literal or identifier starting at i.
+// A doubled quote inside the section is treated as an escaped quote.
+func skipQuoted(s string, i int) int {
+	quote := s[i]
+	for j := i + 1; j < len(s); j++ {
+		if s[j] != quote {
+			continue
+		}
+		if j+1 < len(s) && s[j+1] == quote {
+			j++
+			continue
+		}
+		return j + 1
+	}
+	return len(s)
+}
+
+// escapedQuestion returns the output for an escaped "??".
+// Raw builds keep the escape so the fragment can be rendered again by an outer builder.
+func escapedQuestion(dialect Dialect) string {
+	if _, ok := dialect.(rawDialect); ok {
+		return "??"
+	}
+	return "?"
 }
 
 /*
@@ -880,25 +2743,41 @@ GeneratePlaceholders
 @ Return: String of placeholders separated by comma
 */
 func GeneratePlaceholders(dbType DBType, start, count int) string {
+	return generatePlaceholders(dialectFor(dbType), start, count)
+}
+
+func generatePlaceholders(dialect Dialect, start, count int) string {
 	ph := make([]string, count)
 	for i := 0; i < count; i++ {
-		switch dbType {
-		case PostgreSQL:
-			ph[i] = "$" + strconv.Itoa(start+i)
-		default:
-			ph[i] = "?"
-		}
+		ph[i] = dialect.Placeholder(start + i)
 	}
 	return strings.Join(ph, ", ")
 }
 
-func sanitizeColumns(dbType DBType, columns []string, errRef *error) []string {
+// sanitizeSelectColumns escapes SELECT columns, which may carry an alias ("name AS n").
+func sanitizeSelectColumns(dialect Dialect, columns []string, errRef *error) []string {
+	if len(columns) == 0 {
+		return []string{"*"}
+	}
+	safe := make([]string, len(columns))
+	for i, col := range columns {
+		colEsc, err := escapeAliased(dialect, col)
+		if err != nil {
+			*errRef = err
+			return nil
+		}
+		safe[i] = colEsc
+	}
+	return safe
+}
+
+func sanitizeColumns(dialect Dialect, columns []string, errRef *error) []string {
 	if len(columns) == 0 {
 		return []string{"*"}
 	}
 	safe := make([]string, len(columns))
 	for i, col := range columns {
-		colEsc, err := EscapeIdentifier(dbType, col)
+		colEsc, err := dialect.EscapeIdentifier(col)
 		if err != nil {
 			*errRef = err
 			return nil
@@ -907,3 +2786,18 @@ func sanitizeColumns(dbType DBType, columns []string, errRef *error) []string {
 	}
 	return safe
 }
+
+// questionMarks returns n comma separated "?" placeholders.
+func questionMarks(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+// sortedKeys returns the columns of data in a stable order so built queries are deterministic.
+func sortedKeys(data map[string]interface{}) []string {
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -1,7 +1,13 @@
 package gdct
 
 import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestBuildSelect(t *testing.T) {
@@ -259,3 +265,1348 @@ func BenchmarkBuildComplexQuery(b *testing.B) {
 		}
 	}
 }
+
+// BenchmarkBuildSelectPrepared measures Build alone on a fully configured builder.
+func BenchmarkBuildSelectPrepared(b *testing.B) {
+	qb := BuildSelect(PostgreSQL, "users u", "u.id", "u.name", "p.title").
+		LeftJoin("posts p", "p.user_id = u.id").
+		Where("u.age > ?", 18).
+		Where("u.status = ?", "active").
+		GroupBy("u.id", "u.name").
+		Having("COUNT(p.id) > ?", 5).
+		OrderBy("u.created_at", "DESC", nil)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, _, err := qb.Build(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestSoftDelete(t *testing.T) {
+	query, args, err := BuildDelete(PostgreSQL, "users").
+		SoftDelete("deleted_at").
+		Where("id = ?", 1).
+		Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "UPDATE users SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1 AND users.deleted_at IS NULL"
+	if query != expected {
+		t.Errorf("Expected %q, got %q", expected, query)
+	}
+	if len(args) != 1 || args[0] != 1 {
+		t.Errorf("Expected args [1], got %v", args)
+	}
+
+	query, _, err = BuildSelect(MariaDB, "users").
+		SoftDelete("deleted_at").
+		Where("age > ?", 18).
+		Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected = "SELECT * FROM users WHERE age > ? AND users.deleted_at IS NULL"
+	if query != expected {
+		t.Errorf("Expected %q, got %q", expected, query)
+	}
+
+	query, _, err = BuildSelect(MariaDB, "users").
+		SoftDelete("deleted_at").
+		WithTrashed().
+		Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected = "SELECT * FROM users"
+	if query != expected {
+		t.Errorf("Expected %q, got %q", expected, query)
+	}
+
+	// Both joined tables have deleted_at, so the filter names the main table
+	query, _, err = BuildSelect(PostgreSQL, "users", "users.id", "posts.title").
+		LeftJoin("posts", "posts.user_id = users.id").
+		SoftDelete("deleted_at").
+		Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected = "SELECT users.id, posts.title FROM users LEFT JOIN posts ON posts.user_id = users.id WHERE users.deleted_at IS NULL"
+	if query != expected {
+		t.Errorf("Expected %q, got %q", expected, query)
+	}
+
+	query, _, err = BuildSelect(PostgreSQL, "users", "u.id", "p.title").
+		Alias("u").
+		LeftJoin("posts p", "p.user_id = u.id").
+		SoftDelete("deleted_at").
+		Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected = "SELECT u.id, p.title FROM users AS u LEFT JOIN posts p ON p.user_id = u.id WHERE u.deleted_at IS NULL"
+	if query != expected {
+		t.Errorf("Expected %q, got %q", expected, query)
+	}
+}
+
+func TestOptimisticLock(t *testing.T) {
+	query, args, err := BuildUpdate(Mysql, "users").
+		Set(map[string]interface{}{"name": "John"}).
+		Where("id = ?", 1).
+		OptimisticLock("version", 3).
+		Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "UPDATE users SET name = ?, version = version + 1 WHERE id = ? AND version = ?"
+	if query != expected {
+		t.Errorf("Expected %q, got %q", expected, query)
+	}
+	if len(args) != 3 || args[0] != "John" || args[1] != 1 || args[2] != 3 {
+		t.Errorf("Expected args [John 1 3], got %v", args)
+	}
+
+	query, args, err = BuildUpdate(PostgreSQL, "users").
+		Set(map[string]interface{}{"name": "John"}).
+		OptimisticLock("version", 7).
+		Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected = "UPDATE users SET name = $1, version = version + 1 WHERE version = $2"
+	if query != expected {
+		t.Errorf("Expected %q, got %q", expected, query)
+	}
+	if len(args) != 2 || args[1] != 7 {
+		t.Errorf("Expected version arg 7, got %v", args)
+	}
+
+	_, _, err = BuildSelect(PostgreSQL, "users").OptimisticLock("version", 1).Build()
+	if err == nil {
+		t.Errorf("Expected error for OptimisticLock on SELECT")
+	}
+}
+
+func TestNamedArgReuse(t *testing.T) {
+	query, args, err := BuildSelect(PostgreSQL, "users", "id").
+		Where("status = ?", "active").
+		Where("(created_by = :uid OR updated_by = :uid)", Arg("uid", 42)).
+		Where("meta::text <> ?", "{}").
+		Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "SELECT id FROM users WHERE status = $1 AND (created_by = $2 OR updated_by = $2) AND meta::text <> $3"
+	if query != expected {
+		t.Errorf("Expected %q, got %q", expected, query)
+	}
+	if len(args) != 3 || args[0] != "active" || args[1] != 42 || args[2] != "{}" {
+		t.Errorf("Expected args [active 42 {}], got %v", args)
+	}
+
+	// Positional placeholders need the value once per reference.
+	query, args, err = BuildSelect(Mysql, "users", "id").
+		Where("created_by = :uid OR updated_by = :uid", Arg("uid", 42)).
+		Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected = "SELECT id FROM users WHERE created_by = ? OR updated_by = ?"
+	if query != expected {
+		t.Errorf("Expected %q, got %q", expected, query)
+	}
+	if len(args) != 2 {
+		t.Errorf("Expected 2 args, got %v", args)
+	}
+
+	// Each condition binds its own names.
+	for _, dbType := range []DBType{PostgreSQL, Mysql} {
+		query, args, err = BuildSelect(dbType, "users", "id").
+			Where("a = :x", Arg("x", 1)).
+			Where("b = :x", Arg("x", 2)).
+			Build()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if fmt.Sprint(args) != "[1 2]" {
+			t.Errorf("%s: expected args [1 2], got %v for %q", dbType, args, query)
+		}
+	}
+	expected = "SELECT id FROM users WHERE a = $1 AND b = $2"
+	if query, _, _ = BuildSelect(PostgreSQL, "users", "id").Where("a = :x", Arg("x", 1)).Where("b = :x", Arg("x", 2)).Build(); query != expected {
+		t.Errorf("Expected %q, got %q", expected, query)
+	}
+}
+
+func TestRawValues(t *testing.T) {
+	query, args, err := BuildUpdate(PostgreSQL, "users").
+		Set(map[string]interface{}{
+			"login_count": Raw("login_count + 1"),
+			"name":        "John",
+		}).
+		Where("id = ?", 1).
+		Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "UPDATE users SET login_count = login_count + 1, name = $1 WHERE id = $2"
+	if query != expected {
+		t.Errorf("Expected %q, got %q", expected, query)
+	}
+	if len(args) != 2 || args[0] != "John" || args[1] != 1 {
+		t.Errorf("Expected args [John 1], got %v", args)
+	}
+
+	query, args, err = BuildInsert(Mysql, "users").
+		Values(map[string]interface{}{
+			"created_at": Raw("CURRENT_TIMESTAMP"),
+			"name":       "John",
+		}).
+		Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected = "INSERT INTO users (created_at, name) VALUES (CURRENT_TIMESTAMP, ?)"
+	if query != expected {
+		t.Errorf("Expected %q, got %q", expected, query)
+	}
+	if len(args) != 1 || args[0] != "John" {
+		t.Errorf("Expected args [John], got %v", args)
+	}
+
+	_, _, err = BuildUpdate(PostgreSQL, "users").Set(map[string]interface{}{"name": Raw(" ")}).Build()
+	if err == nil {
+		t.Errorf("Expected error for empty raw expression")
+	}
+}
+
+func TestIncrementDecrement(t *testing.T) {
+	query, args, err := BuildUpdate(PostgreSQL, "accounts").
+		Set(map[string]interface{}{"updated_by": "admin"}).
+		Increment("balance", 100).
+		Decrement("credits", 1).
+		Where("id = ?", 7).
+		Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "UPDATE accounts SET updated_by = $1, balance = balance + $2, credits = credits - $3 WHERE id = $4"
+	if query != expected {
+		t.Errorf("Expected %q, got %q", expected, query)
+	}
+	if len(args) != 4 || args[0] != "admin" || args[1] != 100 || args[2] != 1 || args[3] != 7 {
+		t.Errorf("Expected args [admin 100 1 7], got %v", args)
+	}
+
+	query, args, err = BuildUpdate(Mysql, "posts").Increment("views", 1).Where("id = ?", 3).Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected = "UPDATE posts SET views = views + ? WHERE id = ?"
+	if query != expected {
+		t.Errorf("Expected %q, got %q", expected, query)
+	}
+	if len(args) != 2 || args[0] != 1 || args[1] != 3 {
+		t.Errorf("Expected args [1 3], got %v", args)
+	}
+
+	_, _, err = BuildSelect(PostgreSQL, "posts").Increment("views", 1).Build()
+	if err == nil {
+		t.Errorf("Expected error for Increment on SELECT")
+	}
+}
+
+func TestSetRaw(t *testing.T) {
+	query, args, err := BuildUpdate(PostgreSQL, "users").
+		Set(map[string]interface{}{"name": "John"}).
+		SetRaw("data", "jsonb_set(data, '{k}', ?)", `"v"`).
+		Where("id = ?", 1).
+		Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "UPDATE users SET name = $1, data = jsonb_set(data, '{k}', $2) WHERE id = $3"
+	if query != expected {
+		t.Errorf("Expected %q, got %q", expected, query)
+	}
+	if len(args) != 3 || args[0] != "John" || args[1] != `"v"` || args[2] != 1 {
+		t.Errorf("Expected args [John \"v\" 1], got %v", args)
+	}
+
+	_, _, err = BuildUpdate(PostgreSQL, "users").SetRaw("data", "").Build()
+	if err == nil {
+		t.Errorf("Expected error for empty SetRaw expression")
+	}
+}
+
+func TestSetJSONPath(t *testing.T) {
+	query, args, err := BuildUpdate(PostgreSQL, "users").
+		SetJSONPath("settings", "profile.city", "Seoul").
+		Where("id = ?", 1).
+		Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "UPDATE users SET settings = jsonb_set(settings, '{profile,city}', $1::jsonb) WHERE id = $2"
+	if query != expected {
+		t.Errorf("Expected %q, got %q", expected, query)
+	}
+	if len(args) != 2 || args[0] != `"Seoul"` || args[1] != 1 {
+		t.Errorf("Expected args [\"Seoul\" 1], got %v", args)
+	}
+
+	_, _, err = BuildUpdate(Mysql, "users").SetJSONPath("settings", "profile.city", "Seoul").Build()
+	if !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Expected ErrUnsupported for MySQL, got %v", err)
+	}
+
+	_, _, err = BuildUpdate(PostgreSQL, "users").SetJSONPath("settings", "a}'", 1).Build()
+	if err == nil {
+		t.Errorf("Expected error for invalid JSON path")
+	}
+}
+
+func TestReplacePlaceholdersQuoted(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"Plain", "a = ? AND b = ?", "a = $1 AND b = $2"},
+		{"Single quoted literal", "note = '?' AND id = ?", "note = '?' AND id = $1"},
+		{"Escaped quote in literal", "note = 'it''s ?' AND id = ?", "note = 'it''s ?' AND id = $1"},
+		{"Double quoted identifier", `"what?" = ?`, `"what?" = $1`},
+		{"Escaped question mark", "data ?? 'key' AND id = ?", "data ? 'key' AND id = $1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ReplacePlaceholders(PostgreSQL, tt.input, 1); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+
+	query, args, err := BuildSelect(PostgreSQL, "docs", "id").
+		Where("note <> '?'").
+		Where("data ?? ?", "key").
+		Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "SELECT id FROM docs WHERE note <> '?' AND data ? $1"
+	if query != expected {
+		t.Errorf("Expected %q, got %q", expected, query)
+	}
+	if len(args) != 1 || args[0] != "key" {
+		t.Errorf("Expected args [key], got %v", args)
+	}
+}
+
+func TestWindow(t *testing.T) {
+	qb := BuildSelect(PostgreSQL, "payments", "user_id", "amount")
+	qb.SelectRaw(qb.Window("SUM(amount)", []string{"user_id"}, []OrderSpec{{Column: "ts"}}) + " AS running_total").
+		SelectRaw(qb.Window("RANK()", nil, []OrderSpec{{Column: "amount", Direction: "desc"}}) + " AS amount_rank")
+
+	query, _, err := qb.Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "SELECT user_id, amount, SUM(amount) OVER (PARTITION BY user_id ORDER BY ts) AS running_total, RANK() OVER (ORDER BY amount DESC) AS amount_rank FROM payments"
+	if query != expected {
+		t.Errorf("Expected %q, got %q", expected, query)
+	}
+
+	qb = BuildSelect(PostgreSQL, "payments")
+	qb.SelectRaw(qb.Window("SUM(amount)", []string{"user_id; --"}, nil))
+	if _, _, err := qb.Build(); !errors.Is(err, ErrInvalidIdentifier) {
+		t.Errorf("Expected ErrInvalidIdentifier for invalid partition column, got %v", err)
+	}
+}
+
+func TestSelectRowNumber(t *testing.T) {
+	query, args, err := BuildSelect(PostgreSQL, "posts", "id", "user_id").
+		SelectRowNumber("rn", []string{"user_id"}, []OrderSpec{{Column: "created_at", Direction: "DESC"}, {Column: "id"}}).
+		SelectRank("score_rank", nil, []OrderSpec{{Column: "score", Direction: "DESC"}}).
+		SelectDenseRank("score_dense_rank", nil, []OrderSpec{{Column: "score", Direction: "DESC"}}).
+		Where("published = ?", true).
+		Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "SELECT id, user_id, ROW_NUMBER() OVER (PARTITION BY user_id ORDER BY created_at DESC, id) AS rn, " +
+		"RANK() OVER (ORDER BY score DESC) AS score_rank, DENSE_RANK() OVER (ORDER BY score DESC) AS score_dense_rank " +
+		"FROM posts WHERE published = $1"
+	if query != expected {
+		t.Errorf("Expected %q, got %q", expected, query)
+	}
+	if len(args) != 1 || args[0] != true {
+		t.Errorf("Expected args [true], got %v", args)
+	}
+
+	_, _, err = BuildSelect(PostgreSQL, "posts").SelectRowNumber("rn;", nil, nil).Build()
+	if !errors.Is(err, ErrInvalidIdentifier) {
+		t.Errorf("Expected ErrInvalidIdentifier for invalid alias, got %v", err)
+	}
+}
+
+func TestExplain(t *testing.T) {
+	tests := []struct {
+		name          string
+		qb            *QueryBuilder
+		expectedQuery string
+	}{
+		{
+			name:          "PostgreSQL explain",
+			qb:            BuildSelect(PostgreSQL, "users", "id").Where("age > ?", 18).Explain(),
+			expectedQuery: "EXPLAIN SELECT id FROM users WHERE age > $1",
+		},
+		{
+			name:          "PostgreSQL explain analyze",
+			qb:            BuildSelect(PostgreSQL, "users", "id").Where("age > ?", 18).ExplainAnalyze(),
+			expectedQuery: "EXPLAIN ANALYZE SELECT id FROM users WHERE age > $1",
+		},
+		{
+			name:          "Mysql explain",
+			qb:            BuildSelect(Mysql, "users", "id").Where("age > ?", 18).Explain(),
+			expectedQuery: "EXPLAIN SELECT id FROM users WHERE age > ?",
+		},
+		{
+			name:          "Mysql explain analyze",
+			qb:            BuildSelect(Mysql, "users", "id").Where("age > ?", 18).ExplainAnalyze(),
+			expectedQuery: "EXPLAIN ANALYZE SELECT id FROM users WHERE age > ?",
+		},
+		{
+			name:          "MariaDB analyze",
+			qb:            BuildSelect(MariaDB, "users", "id").Where("age > ?", 18).ExplainAnalyze(),
+			expectedQuery: "ANALYZE SELECT id FROM users WHERE age > ?",
+		},
+		{
+			name:          "Sqlite explain",
+			qb:            BuildSelect(Sqlite, "users", "id").Where("age > ?", 18).Explain(),
+			expectedQuery: "EXPLAIN QUERY PLAN SELECT id FROM users WHERE age > ?",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args, err := tt.qb.Build()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if query != tt.expectedQuery {
+				t.Errorf("Expected %q, got %q", tt.expectedQuery, query)
+			}
+			if len(args) != 1 {
+				t.Errorf("Expected 1 arg, got %d", len(args))
+			}
+		})
+	}
+
+	_, _, err := BuildSelect(Sqlite, "users").ExplainAnalyze().Build()
+	if !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Expected ErrUnsupported for Sqlite, got %v", err)
+	}
+
+	writes := map[string]*QueryBuilder{
+		"INSERT": BuildInsert(PostgreSQL, "users").Values(map[string]interface{}{"name": "John"}),
+		"UPDATE": BuildUpdate(PostgreSQL, "users").Set(map[string]interface{}{"name": "John"}).Where("id = ?", 1),
+		"DELETE": BuildDelete(PostgreSQL, "users").Where("id = ?", 1),
+	}
+	for op, qb := range writes {
+		if _, _, err := qb.Clone().Explain().Build(); err == nil {
+			t.Errorf("Expected error for Explain on %s", op)
+		}
+		if _, _, err := qb.ExplainAnalyze().Build(); err == nil {
+			t.Errorf("Expected error for ExplainAnalyze on %s", op)
+		}
+	}
+}
+
+func TestBuildArgsNotShared(t *testing.T) {
+	builders := map[string]*QueryBuilder{
+		"SELECT": BuildSelect(PostgreSQL, "users").SelectRaw("COALESCE(nick, ?) AS nick", "none").Where("age > ?", 18).Limit(10),
+		"INSERT": BuildInsert(PostgreSQL, "users").Values(map[string]interface{}{"name": "John", "age": 30}),
+		"UPDATE": BuildUpdate(PostgreSQL, "users").Where("id = ?", 1).Set(map[string]interface{}{"name": "John"}),
+		"DELETE": BuildDelete(PostgreSQL, "users").Where("id = ?", 1),
+	}
+
+	for op, qb := range builders {
+		t.Run(op, func(t *testing.T) {
+			_, first, err := qb.Build()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			expected := fmt.Sprint(first)
+			for i := range first {
+				first[i] = "mutated"
+			}
+
+			_, second, err := qb.Build()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got := fmt.Sprint(second); got != expected {
+				t.Errorf("Expected args %s, got %s", expected, got)
+			}
+		})
+	}
+}
+
+func TestUpdateWhereBeforeSet(t *testing.T) {
+	query, args, err := BuildUpdate(PostgreSQL, "users").
+		Where("id = ?", 7).
+		Where("status = ?", "active").
+		Set(map[string]interface{}{"name": "John", "age": 30}).
+		SetRaw("updated_at", "NOW()").
+		Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "UPDATE users SET age = $1, name = $2, updated_at = NOW() WHERE id = $3 AND status = $4"
+	if query != expected {
+		t.Errorf("Expected %q, got %q", expected, query)
+	}
+	if got := fmt.Sprint(args); got != "[30 John 7 active]" {
+		t.Errorf("Expected args [30 John 7 active], got %s", got)
+	}
+}
+
+func TestWhereNullSafeEq(t *testing.T) {
+	tests := []struct {
+		dbType        DBType
+		expectedQuery string
+	}{
+		{PostgreSQL, "SELECT * FROM users WHERE deleted_by IS NOT DISTINCT FROM $1"},
+		{Sqlite, "SELECT * FROM users WHERE deleted_by IS ?"},
+		{Mysql, "SELECT * FROM users WHERE deleted_by <=> ?"},
+		{MariaDB, "SELECT * FROM users WHERE deleted_by <=> ?"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dbType.String(), func(t *testing.T) {
+			query, args, err := BuildSelect(tt.dbType, "users").WhereNullSafeEq("deleted_by", nil).Build()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if query != tt.expectedQuery {
+				t.Errorf("Expected %q, got %q", tt.expectedQuery, query)
+			}
+			if len(args) != 1 || args[0] != nil {
+				t.Errorf("Expected a single nil arg, got %v", args)
+			}
+		})
+	}
+
+	_, _, err := BuildSelect(Mysql, "users").WhereNullSafeEq("id; --", 1).Build()
+	if !errors.Is(err, ErrInvalidIdentifier) {
+		t.Errorf("Expected ErrInvalidIdentifier, got %v", err)
+	}
+
+	conn := openTestSqlite(t, DBConfig{})
+	if err := conn.SqCreateTable([]string{"CREATE TABLE members (name TEXT NOT NULL, deleted_by INTEGER)"}); err != nil {
+		t.Fatalf("Create table failed: %v", err)
+	}
+	if _, err := conn.Exec("INSERT INTO members (name, deleted_by) VALUES (?, ?), (?, ?)", "John", 30, "Jane", nil); err != nil {
+		t.Fatalf("Insert members failed: %v", err)
+	}
+	for _, tt := range []struct {
+		value    interface{}
+		expected string
+	}{{nil, "Jane"}, {30, "John"}} {
+		names, err := Pluck[string](conn, BuildSelect(Sqlite, "members", "name").WhereNullSafeEq("deleted_by", tt.value), "name")
+		if err != nil {
+			t.Fatalf("Pluck failed: %v", err)
+		}
+		if len(names) != 1 || names[0] != tt.expected {
+			t.Errorf("Expected [%s] for %v, got %v", tt.expected, tt.value, names)
+		}
+	}
+}
+
+func TestWhereIn(t *testing.T) {
+	ids := []interface{}{1, 2, 3}
+	qb := BuildSelect(PostgreSQL, "users", "id").WhereIn("id", ids)
+	// Changing the caller's slice after the call must not change the query
+	ids[0] = 99
+	query, args, err := qb.Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "SELECT id FROM users WHERE id IN ($1, $2, $3)"
+	if query != expected {
+		t.Errorf("Expected %q, got %q", expected, query)
+	}
+	if got := fmt.Sprint(args); got != "[1 2 3]" {
+		t.Errorf("Expected args [1 2 3], got %s", got)
+	}
+
+	_, _, err = BuildSelect(Mysql, "users").WhereIn("id", nil).Build()
+	if err == nil {
+		t.Errorf("Expected error for empty WhereIn values")
+	}
+}
+
+func TestWhereInArray(t *testing.T) {
+	values := []interface{}{1, 2, 3, 4, 5}
+
+	query, args, err := BuildSelect(PostgreSQL, "users").WhereInArray("id", values).Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if expected := "SELECT * FROM users WHERE id = ANY($1)"; query != expected {
+		t.Errorf("Expected %q, got %q", expected, query)
+	}
+	if len(args) != 1 {
+		t.Fatalf("Expected 1 arg for PostgreSQL, got %d", len(args))
+	}
+	if value, err := args[0].(driver.Valuer).Value(); err != nil || value != "{1,2,3,4,5}" {
+		t.Errorf("Expected array literal {1,2,3,4,5}, got %v (%v)", value, err)
+	}
+
+	query, args, err = BuildSelect(Mysql, "users").WhereInArray("id", values).Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if expected := "SELECT * FROM users WHERE id IN (?, ?, ?, ?, ?)"; query != expected {
+		t.Errorf("Expected %q, got %q", expected, query)
+	}
+	if len(args) != len(values) {
+		t.Errorf("Expected %d args for Mysql, got %d", len(values), len(args))
+	}
+}
+
+func TestOrderByMultiNulls(t *testing.T) {
+	specs := []OrderSpec{
+		{Column: "last_login", Direction: "desc", Nulls: NullsLast},
+		{Column: "name", Nulls: NullsFirst},
+		{Column: "id"},
+	}
+
+	tests := []struct {
+		dbType        DBType
+		expectedQuery string
+	}{
+		{PostgreSQL, "SELECT * FROM users ORDER BY last_login DESC NULLS LAST, name NULLS FIRST, id"},
+		{Sqlite, "SELECT * FROM users ORDER BY last_login DESC NULLS LAST, name NULLS FIRST, id"},
+		{Mysql, "SELECT * FROM users ORDER BY last_login IS NULL, last_login DESC, name IS NULL DESC, name, id"},
+		{MariaDB, "SELECT * FROM users ORDER BY last_login IS NULL, last_login DESC, name IS NULL DESC, name, id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dbType.String(), func(t *testing.T) {
+			query, _, err := BuildSelect(tt.dbType, "users").OrderByMulti(specs...).Build()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if query != tt.expectedQuery {
+				t.Errorf("Expected %q, got %q", tt.expectedQuery, query)
+			}
+		})
+	}
+
+	_, _, err := BuildSelect(PostgreSQL, "users").OrderByMulti(OrderSpec{Column: "id", Nulls: "MIDDLE"}).Build()
+	if err == nil {
+		t.Errorf("Expected error for invalid nulls order")
+	}
+}
+
+func TestOrderByRandom(t *testing.T) {
+	tests := []struct {
+		dbType        DBType
+		expectedQuery string
+	}{
+		{PostgreSQL, "SELECT * FROM users ORDER BY RANDOM() LIMIT $1"},
+		{Sqlite, "SELECT * FROM users ORDER BY RANDOM() LIMIT ?"},
+		{Mysql, "SELECT * FROM users ORDER BY RAND() LIMIT ?"},
+		{MariaDB, "SELECT * FROM users ORDER BY RAND() LIMIT ?"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dbType.String(), func(t *testing.T) {
+			query, args, err := BuildSelect(tt.dbType, "users").OrderByRandom().Limit(5).Build()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if query != tt.expectedQuery {
+				t.Errorf("Expected %q, got %q", tt.expectedQuery, query)
+			}
+			if len(args) != 1 || args[0] != int64(5) {
+				t.Errorf("Expected limit arg 5, got %v", args)
+			}
+		})
+	}
+}
+
+func TestSelectGroupConcat(t *testing.T) {
+	tests := []struct {
+		dbType        DBType
+		expectedQuery string
+		expectedArgs  int
+	}{
+		{PostgreSQL, "SELECT user_id, STRING_AGG(tag, $1) AS tags FROM posts GROUP BY user_id", 1},
+		{Sqlite, "SELECT user_id, GROUP_CONCAT(tag, ?) AS tags FROM posts GROUP BY user_id", 1},
+		{Mysql, "SELECT user_id, GROUP_CONCAT(tag SEPARATOR ', ') AS tags FROM posts GROUP BY user_id", 0},
+		{MariaDB, "SELECT user_id, GROUP_CONCAT(tag SEPARATOR ', ') AS tags FROM posts GROUP BY user_id", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dbType.String(), func(t *testing.T) {
+			query, args, err := BuildSelect(tt.dbType, "posts", "user_id").
+				SelectGroupConcat("tag", ", ", "tags").
+				GroupBy("user_id").
+				Build()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if query != tt.expectedQuery {
+				t.Errorf("Expected %q, got %q", tt.expectedQuery, query)
+			}
+			if len(args) != tt.expectedArgs {
+				t.Errorf("Expected %d args, got %d", tt.expectedArgs, len(args))
+			}
+		})
+	}
+
+	_, _, err := BuildSelect(Mysql, "posts").SelectGroupConcat("tag", "'; DROP TABLE posts; --", "tags").Build()
+	if err == nil {
+		t.Errorf("Expected error for quoted Mysql separator")
+	}
+}
+
+func TestWhereFullText(t *testing.T) {
+	tests := []struct {
+		name          string
+		dbType        DBType
+		columns       []string
+		expectedQuery string
+	}{
+		{"PostgreSQL single column", PostgreSQL, []string{"body"}, "SELECT * FROM posts WHERE to_tsvector(body) @@ plainto_tsquery($1)"},
+		{"PostgreSQL columns", PostgreSQL, []string{"title", "body"}, "SELECT * FROM posts WHERE to_tsvector(concat_ws(' ', title, body)) @@ plainto_tsquery($1)"},
+		{"Mysql", Mysql, []string{"title", "body"}, "SELECT * FROM posts WHERE MATCH (title, body) AGAINST (? IN NATURAL LANGUAGE MODE)"},
+		{"MariaDB", MariaDB, []string{"title"}, "SELECT * FROM posts WHERE MATCH (title) AGAINST (? IN NATURAL LANGUAGE MODE)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args, err := BuildSelect(tt.dbType, "posts").WhereFullText(tt.columns, "query builder").Build()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if query != tt.expectedQuery {
+				t.Errorf("Expected %q, got %q", tt.expectedQuery, query)
+			}
+			if len(args) != 1 || args[0] != "query builder" {
+				t.Errorf("Expected search text arg, got %v", args)
+			}
+		})
+	}
+
+	_, _, err := BuildSelect(Sqlite, "posts").WhereFullText([]string{"body"}, "query").Build()
+	if !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Expected ErrUnsupported for Sqlite, got %v", err)
+	}
+}
+
+func TestOrderByStrict(t *testing.T) {
+	allowed := map[string]bool{"name": true, "created_at": true}
+
+	query, _, err := BuildSelect(PostgreSQL, "users").OrderByStrict("name", "desc", allowed).Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if expected := "SELECT * FROM users ORDER BY name DESC"; query != expected {
+		t.Errorf("Expected %q, got %q", expected, query)
+	}
+
+	_, _, err = BuildSelect(PostgreSQL, "users").OrderByStrict("password", "ASC", allowed).Build()
+	if !errors.Is(err, ErrDisallowedOrderColumn) {
+		t.Errorf("Expected ErrDisallowedOrderColumn, got %v", err)
+	}
+}
+
+func TestDefaultOrderColumn(t *testing.T) {
+	allowed := map[string]bool{"name": true}
+
+	query, _, err := BuildSelect(PostgreSQL, "events").
+		DefaultOrderColumn("occurred_at").
+		OrderBy("password", "DESC", allowed).
+		Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if expected := "SELECT * FROM events ORDER BY occurred_at DESC"; query != expected {
+		t.Errorf("Expected %q, got %q", expected, query)
+	}
+
+	query, _, err = BuildSelect(PostgreSQL, "events").
+		DefaultOrderColumn("occurred_at").
+		OrderBy("name", "ASC", allowed).
+		Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if expected := "SELECT * FROM events ORDER BY name ASC"; query != expected {
+		t.Errorf("Expected %q, got %q", expected, query)
+	}
+
+	query, _, _ = BuildSelect(PostgreSQL, "events").OrderBy("password", "ASC", allowed).Build()
+	if expected := "SELECT * FROM events ORDER BY id ASC"; query != expected {
+		t.Errorf("Expected %q without a default order column, got %q", expected, query)
+	}
+
+	_, _, err = BuildSelect(PostgreSQL, "events").DefaultOrderColumn("id desc").Build()
+	if !errors.Is(err, ErrInvalidIdentifier) {
+		t.Errorf("Expected ErrInvalidIdentifier, got %v", err)
+	}
+}
+
+func TestWhereRaw(t *testing.T) {
+	query, args, err := BuildSelect(PostgreSQL, "bookings").
+		Where("room_id = ?", 4).
+		WhereRaw("tsrange(starts_at, ends_at) && tsrange(?, ?)", "2024-05-01 10:00", "2024-05-01 12:00").
+		Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "SELECT * FROM bookings WHERE room_id = $1 AND tsrange(starts_at, ends_at) && tsrange($2, $3)"
+	if query != expected {
+		t.Errorf("Expected %q, got %q", expected, query)
+	}
+	if len(args) != 3 || args[1] != "2024-05-01 10:00" || args[2] != "2024-05-01 12:00" {
+		t.Errorf("Expected room id and range args, got %v", args)
+	}
+}
+
+func TestHavingIn(t *testing.T) {
+	counts := []interface{}{1, 2, 3}
+	qb := BuildSelect(PostgreSQL, "posts", "user_id").
+		SelectRaw("COUNT(*) AS post_count").
+		Where("published = ?", true).
+		GroupBy("user_id").
+		HavingIn("COUNT(*)", counts)
+	// Changing the caller's slice after the call must not change the query
+	counts[0] = 99
+	query, args, err := qb.Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "SELECT user_id, COUNT(*) AS post_count FROM posts WHERE published = $1 GROUP BY user_id HAVING COUNT(*) IN ($2, $3, $4)"
+	if query != expected {
+		t.Errorf("Expected %q, got %q", expected, query)
+	}
+	if got := fmt.Sprint(args); got != "[true 1 2 3]" {
+		t.Errorf("Expected args [true 1 2 3], got %s", got)
+	}
+
+	_, _, err = BuildSelect(Mysql, "posts").GroupBy("user_id").HavingIn("COUNT(*)", nil).Build()
+	if err == nil {
+		t.Errorf("Expected error for empty HavingIn values")
+	}
+}
+
+func TestLimitOffset64(t *testing.T) {
+	offset := int64(math.MaxInt32) + 10
+	query, args, err := BuildSelect(PostgreSQL, "events").Limit64(100).Offset64(offset).Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if expected := "SELECT * FROM events LIMIT $1 OFFSET $2"; query != expected {
+		t.Errorf("Expected %q, got %q", expected, query)
+	}
+	if len(args) != 2 || args[0] != int64(100) || args[1] != offset {
+		t.Errorf("Expected int64 args [100 %d], got %#v", offset, args)
+	}
+
+	_, args, _ = BuildSelect(Mysql, "events").Limit(10).Offset(20).Build()
+	if args[0] != int64(10) || args[1] != int64(20) {
+		t.Errorf("Expected Limit and Offset to bind int64 args, got %#v", args)
+	}
+}
+
+func TestQueryBuilderString(t *testing.T) {
+	qb := BuildSelect(PostgreSQL, "users", "id", "name").Where("age > ?", 18).Limit(10)
+	query, _, err := qb.Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if qb.String() != query {
+		t.Errorf("Expected %q, got %q", query, qb.String())
+	}
+	if got := fmt.Sprintf("%s", qb); got != query {
+		t.Errorf("Expected %q from fmt, got %q", query, got)
+	}
+
+	invalid := BuildSelect(PostgreSQL, "users; DROP TABLE users")
+	if got := invalid.String(); !strings.Contains(got, ErrInvalidIdentifier.Error()) {
+		t.Errorf("Expected error text, got %q", got)
+	}
+}
+
+func TestBuildQuery(t *testing.T) {
+	qb := BuildSelect(PostgreSQL, "users", "id").Where("age > ?", 18).WhereIn("status", []interface{}{"active", "pending"})
+
+	query, args, err := qb.Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	built, err := qb.BuildQuery()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if built.SQL != query {
+		t.Errorf("Expected SQL %q, got %q", query, built.SQL)
+	}
+	if fmt.Sprint(built.Args) != fmt.Sprint(args) {
+		t.Errorf("Expected args %v, got %v", args, built.Args)
+	}
+	if built.String() != query {
+		t.Errorf("Expected String() %q, got %q", query, built.String())
+	}
+
+	if _, err := BuildSelect(DBType("oracle"), "users").BuildQuery(); !errors.Is(err, ErrInvalidDBType) {
+		t.Errorf("Expected ErrInvalidDBType, got %v", err)
+	}
+}
+
+func TestDefaultValues(t *testing.T) {
+	tests := []struct {
+		dbType        DBType
+		expectedQuery string
+	}{
+		{PostgreSQL, "INSERT INTO counters DEFAULT VALUES RETURNING id"},
+		{Sqlite, "INSERT INTO counters DEFAULT VALUES RETURNING id"},
+		{Mysql, "INSERT INTO counters () VALUES ()"},
+		{MariaDB, "INSERT INTO counters () VALUES () RETURNING id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dbType.String(), func(t *testing.T) {
+			qb := BuildInsert(tt.dbType, "counters").DefaultValues()
+			if tt.dbType.Supports(FeatureReturning) {
+				qb.Returning("id")
+			}
+			query, args, err := qb.Build()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if query != tt.expectedQuery {
+				t.Errorf("Expected %q, got %q", tt.expectedQuery, query)
+			}
+			if len(args) != 0 {
+				t.Errorf("Expected no args, got %v", args)
+			}
+		})
+	}
+
+	conn := openTestSqlite(t, DBConfig{})
+	if err := conn.SqCreateTable([]string{"CREATE TABLE counters (id INTEGER PRIMARY KEY AUTOINCREMENT, hits INTEGER NOT NULL DEFAULT 0)"}); err != nil {
+		t.Fatalf("Create table failed: %v", err)
+	}
+	if _, err := conn.ExecBuilder(BuildInsert(Sqlite, "counters").DefaultValues()); err != nil {
+		t.Errorf("Insert default values failed: %v", err)
+	}
+}
+
+func TestWhereRanges(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0)
+
+	tests := []struct {
+		name          string
+		qb            *QueryBuilder
+		expectedQuery string
+	}{
+		{
+			name:          "Between time",
+			qb:            BuildSelect(PostgreSQL, "orders").WhereBetweenTime("created_at", start, end),
+			expectedQuery: "SELECT * FROM orders WHERE created_at BETWEEN $1 AND $2",
+		},
+		{
+			name:          "Not between",
+			qb:            BuildSelect(Mysql, "orders").WhereNotBetween("amount", 10, 100),
+			expectedQuery: "SELECT * FROM orders WHERE amount NOT BETWEEN ? AND ?",
+		},
+		{
+			name:          "Exclusive",
+			qb:            BuildSelect(PostgreSQL, "orders").WhereBetweenExclusive("amount", 10, 100).Where("status = ?", "paid"),
+			expectedQuery: "SELECT * FROM orders WHERE (amount > $1 AND amount < $2) AND status = $3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args, err := tt.qb.Build()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if query != tt.expectedQuery {
+				t.Errorf("Expected %q, got %q", tt.expectedQuery, query)
+			}
+			if len(args) < 2 {
+				t.Errorf("Expected both bounds as args, got %v", args)
+			}
+		})
+	}
+
+	_, _, err := BuildSelect(PostgreSQL, "orders").WhereBetweenTime("created_at", end, start).Build()
+	if err == nil {
+		t.Errorf("Expected error for reversed time range")
+	}
+}
+
+func TestWhenUnless(t *testing.T) {
+	build := func(name string, minAge int) string {
+		query, _, err := BuildSelect(PostgreSQL, "users").
+			When(name != "", func(qb *QueryBuilder) *QueryBuilder {
+				return qb.Where("name = ?", name)
+			}).
+			Unless(minAge == 0, func(qb *QueryBuilder) *QueryBuilder {
+				return qb.Where("age >= ?", minAge)
+			}).
+			Build()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return query
+	}
+
+	tests := []struct {
+		name          string
+		minAge        int
+		expectedQuery string
+	}{
+		{"John", 30, "SELECT * FROM users WHERE name = $1 AND age >= $2"},
+		{"John", 0, "SELECT * FROM users WHERE name = $1"},
+		{"", 30, "SELECT * FROM users WHERE age >= $1"},
+		{"", 0, "SELECT * FROM users"},
+	}
+
+	for _, tt := range tests {
+		if got := build(tt.name, tt.minAge); got != tt.expectedQuery {
+			t.Errorf("Expected %q, got %q", tt.expectedQuery, got)
+		}
+	}
+}
+
+func TestApplyEach(t *testing.T) {
+	type filter struct {
+		column string
+		value  interface{}
+	}
+	filters := []filter{{"status", "active"}, {"role", "admin"}, {"tenant_id", 7}}
+
+	query, args, err := ApplyEach(BuildSelect(PostgreSQL, "users"), filters, func(qb *QueryBuilder, f filter) *QueryBuilder {
+		return qb.Where(f.column+" = ?", f.value)
+	}).Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "SELECT * FROM users WHERE status = $1 AND role = $2 AND tenant_id = $3"
+	if query != expected {
+		t.Errorf("Expected %q, got %q", expected, query)
+	}
+	if len(args) != 3 {
+		t.Errorf("Expected 3 args, got %d", len(args))
+	}
+
+	calls := 0
+	_, _, err = ApplyEach(BuildSelect(PostgreSQL, "users"), []string{"ok", "bad column", "never"}, func(qb *QueryBuilder, column string) *QueryBuilder {
+		calls++
+		return qb.WhereIn(column, []interface{}{1})
+	}).Build()
+	if !errors.Is(err, ErrInvalidIdentifier) {
+		t.Errorf("Expected ErrInvalidIdentifier, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected ApplyEach to stop after the failing item, got %d calls", calls)
+	}
+}
+
+func TestAddWhereIfNotEmpty(t *testing.T) {
+	empty := ""
+	name := "kim"
+	zero := 0
+	var nilInt *int
+	var nilMap map[string]int
+
+	tests := []struct {
+		name     string
+		value    interface{}
+		skipZero bool
+		applied  bool
+	}{
+		{"nil", nil, false, false},
+		{"empty string", "", false, false},
+		{"string", "kim", false, true},
+		{"empty string pointer", &empty, false, false},
+		{"string pointer", &name, false, true},
+		{"nil int pointer", nilInt, false, false},
+		{"zero int", 0, false, true},
+		{"zero int skipped", 0, true, false},
+		{"zero float", 0.0, false, true},
+		{"zero float skipped", 0.0, true, false},
+		{"zero uint skipped", uint(0), true, false},
+		{"int", 7, false, true},
+		{"int with skipped zeros", 7, true, true},
+		{"zero int pointer", &zero, false, true},
+		{"zero int pointer skipped", &zero, true, false},
+		{"empty slice", []int{}, false, false},
+		{"nil slice", []string(nil), false, false},
+		{"slice", []int{1}, false, true},
+		{"nil map", nilMap, false, false},
+		{"empty map", map[string]int{}, false, false},
+		{"map", map[string]int{"a": 1}, false, true},
+		{"false", false, false, true},
+		{"zero time", time.Time{}, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			qb := BuildSelect(PostgreSQL, "users")
+			if tt.skipZero {
+				qb.SkipZeroValues()
+			}
+			query, args, err := qb.AddWhereIfNotEmpty("name = ?", tt.value).Build()
+			if err != nil {
+				t.Fatalf("Build error: %v", err)
+			}
+			applied := strings.Contains(query, "WHERE")
+			if applied != tt.applied {
+				t.Errorf("Expected applied %v, got %q %v", tt.applied, query, args)
+			}
+		})
+	}
+}
+
+func TestAddWhereIf(t *testing.T) {
+	nonNegative := func(v interface{}) bool {
+		n, ok := v.(int)
+		return ok && n >= 0
+	}
+
+	query, args, err := BuildSelect(PostgreSQL, "orders").
+		AddWhereIf("quantity = ?", -1, nonNegative).
+		AddWhereIf("price = ?", 0, nonNegative).
+		Build()
+	if err != nil {
+		t.Fatalf("Build error: %v", err)
+	}
+
+	expected := "SELECT * FROM orders WHERE price = $1"
+	if query != expected {
+		t.Errorf("Expected %q, got %q", expected, query)
+	}
+	if len(args) != 1 || args[0] != 0 {
+		t.Errorf("Expected args [0], got %v", args)
+	}
+
+	_, _, err = BuildSelect(PostgreSQL, "orders").AddWhereIf("price = ?", 0, nil).Build()
+	if err == nil {
+		t.Errorf("Expected error for nil predicate")
+	}
+}
+
+func TestSelectCoalesce(t *testing.T) {
+	tests := []struct {
+		name          string
+		dbType        DBType
+		exprs         []interface{}
+		expectedQuery string
+		expectedArgs  []interface{}
+	}{
+		{
+			name:          "columns",
+			dbType:        PostgreSQL,
+			exprs:         []interface{}{"nickname", "name"},
+			expectedQuery: "SELECT id, COALESCE(nickname, name) AS display_name FROM users",
+		},
+		{
+			name:          "raw fallback",
+			dbType:        Mysql,
+			exprs:         []interface{}{"nickname", Raw("'anonymous'")},
+			expectedQuery: "SELECT id, COALESCE(nickname, 'anonymous') AS display_name FROM users",
+		},
+		{
+			name:          "bound fallback",
+			dbType:        PostgreSQL,
+			exprs:         []interface{}{"nickname", "name", 0},
+			expectedQuery: "SELECT id, COALESCE(nickname, name, $1) AS display_name FROM users",
+			expectedArgs:  []interface{}{0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args, err := BuildSelect(tt.dbType, "users", "id").
+				SelectCoalesce("display_name", tt.exprs...).
+				Build()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if query != tt.expectedQuery {
+				t.Errorf("Expected %q, got %q", tt.expectedQuery, query)
+			}
+			if fmt.Sprint(args) != fmt.Sprint(tt.expectedArgs) {
+				t.Errorf("Expected args %v, got %v", tt.expectedArgs, args)
+			}
+		})
+	}
+
+	if _, _, err := BuildSelect(PostgreSQL, "users").SelectCoalesce("name", "name").Build(); err == nil {
+		t.Errorf("Expected error for a single COALESCE expression")
+	}
+	if _, _, err := BuildSelect(PostgreSQL, "users").SelectCoalesce("name", "name", "n/a").Build(); err == nil {
+		t.Errorf("Expected error for a string that is not a column name")
+	}
+}
+
+func TestSelectNullIf(t *testing.T) {
+	query, args, err := BuildSelect(PostgreSQL, "stats", "id").
+		SelectNullIf("ratio_base", "total", 0).
+		Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := "SELECT id, NULLIF(total, $1) AS ratio_base FROM stats"
+	if query != expected {
+		t.Errorf("Expected %q, got %q", expected, query)
+	}
+	if len(args) != 1 || args[0] != 0 {
+		t.Errorf("Expected args [0], got %v", args)
+	}
+
+	query, _, err = BuildSelect(Sqlite, "users", "id").SelectNullIf("email", "email", Raw("''")).Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected = "SELECT id, NULLIF(email, '') AS email FROM users"
+	if query != expected {
+		t.Errorf("Expected %q, got %q", expected, query)
+	}
+}
+
+func TestNow(t *testing.T) {
+	for _, dbType := range []DBType{PostgreSQL, MariaDB, Mysql, Sqlite} {
+		t.Run(dbType.String(), func(t *testing.T) {
+			query, args, err := BuildInsert(dbType, "users").
+				Values(map[string]interface{}{"created_at": Now}).
+				Build()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			expected := "INSERT INTO users (created_at) VALUES (CURRENT_TIMESTAMP)"
+			if query != expected {
+				t.Errorf("Expected %q, got %q", expected, query)
+			}
+			if len(args) != 0 {
+				t.Errorf("Expected no args, got %v", args)
+			}
+
+			query, args, err = BuildUpdate(dbType, "users").
+				Set(map[string]interface{}{"updated_at": Now}).
+				Where("id = ?", 1).
+				Build()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !strings.HasPrefix(query, "UPDATE users SET updated_at = CURRENT_TIMESTAMP WHERE id = ") {
+				t.Errorf("Expected CURRENT_TIMESTAMP assignment, got %q", query)
+			}
+			if len(args) != 1 {
+				t.Errorf("Expected 1 arg, got %v", args)
+			}
+		})
+	}
+}
+
+func TestMustBuild(t *testing.T) {
+	query, args := BuildSelect(PostgreSQL, "users", "id").Where("age > ?", 18).MustBuild()
+	expected := "SELECT id FROM users WHERE age > $1"
+	if query != expected {
+		t.Errorf("Expected %q, got %q", expected, query)
+	}
+	if len(args) != 1 {
+		t.Errorf("Expected 1 arg, got %v", args)
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Expected MustBuild to panic on an errored builder")
+		}
+	}()
+	BuildSelect(PostgreSQL, "users;").MustBuild()
+}
+
+func TestValuesOrdered(t *testing.T) {
+	query, args, err := BuildInsert(PostgreSQL, "events").
+		ValuesOrdered([]string{"tenant_id", "created_at", "kind", "id"}, []interface{}{7, Now, "login", 42}).
+		Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "INSERT INTO events (tenant_id, created_at, kind, id) VALUES ($1, CURRENT_TIMESTAMP, $2, $3)"
+	if query != expected {
+		t.Errorf("Expected %q, got %q", expected, query)
+	}
+	if fmt.Sprint(args) != "[7 login 42]" {
+		t.Errorf("Expected args [7 login 42], got %v", args)
+	}
+
+	_, _, err = BuildInsert(PostgreSQL, "events").ValuesOrdered([]string{"a", "b"}, []interface{}{1}).Build()
+	if err == nil {
+		t.Errorf("Expected error for mismatched columns and values")
+	}
+}
+
+func TestSetOrdered(t *testing.T) {
+	query, args, err := BuildUpdate(Mysql, "accounts").
+		SetOrdered([]string{"balance", "updated_at", "status"}, []interface{}{100, Now, "active"}).
+		Where("id = ?", 1).
+		Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "UPDATE accounts SET balance = ?, updated_at = CURRENT_TIMESTAMP, status = ? WHERE id = ?"
+	if query != expected {
+		t.Errorf("Expected %q, got %q", expected, query)
+	}
+	if fmt.Sprint(args) != "[100 active 1]" {
+		t.Errorf("Expected args [100 active 1], got %v", args)
+	}
+
+	_, _, err = BuildInsert(Mysql, "accounts").SetOrdered([]string{"a"}, []interface{}{1}).Build()
+	if err == nil {
+		t.Errorf("Expected error for SetOrdered on INSERT")
+	}
+}
@@ -0,0 +1,364 @@
+package gdct
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// maxBindParams is the number of bind parameters a single statement may use.
+// SQLite builds before 3.32 allow 999, so that limit is used for every SQLite version.
+var maxBindParams = map[DBType]int{
+	PostgreSQL: 65535,
+	MariaDB:    65535,
+	Mysql:      65535,
+	Sqlite:     999,
+}
+
+/*
+CopyFrom
+
+@ table: Target table
+@ columns: Columns of every row
+@ rows: Row values in column order
+@ Return: Number of rows loaded and error if any
+
+PostgreSQL streams the rows with COPY inside a transaction.
+Other databases insert them with multi-row INSERT statements sized to the bind parameter limit, also in one transaction.
+*/
+func (connect *DataBaseConnector) CopyFrom(table string, columns []string, rows [][]interface{}) (int64, error) {
+	if len(rows) == 0 {
+		return 0, nil
+	}
+	if len(columns) == 0 {
+		return 0, fmt.Errorf("copy requires at least one column")
+	}
+	if connect.dbType != PostgreSQL {
+		return connect.insertRowsChunked(context.Background(), table, columns, rows, 0)
+	}
+
+	if err := ValidateIdentifier(table); err != nil {
+		return 0, fmt.Errorf("invalid table name: %w", err)
+	}
+	for _, col := range columns {
+		if err := ValidateIdentifier(col); err != nil {
+			return 0, fmt.Errorf("invalid column name: %w", err)
+		}
+	}
+
+	copyQuery := pq.CopyIn(table, columns...)
+	if schema, name, ok := strings.Cut(table, "."); ok {
+		copyQuery = pq.CopyInSchema(schema, name, columns...)
+	}
+
+	err := connect.WithTransaction(context.Background(), nil, func(tx *sql.Tx) error {
+		stmt, err := tx.Prepare(copyQuery)
+		if err != nil {
+			return fmt.Errorf("prepare copy error: %w", err)
+		}
+		defer stmt.Close()
+
+		for i, row := range rows {
+			if len(row) != len(columns) {
+				return fmt.Errorf("copy row %d has %d values, expected %d", i, len(row), len(columns))
+			}
+			if _, err := stmt.Exec(row...); err != nil {
+				return fmt.Errorf("copy row error: %w", err)
+			}
+		}
+
+		// Flush the buffered rows
+		if _, err := stmt.Exec(); err != nil {
+			return fmt.Errorf("copy flush error: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(len(rows)), nil
+}
+
+// insertRowsChunked inserts rows with multi-row INSERT statements in a single transaction.
+// batchSize <= 0 uses the largest batch the bind parameter limit allows; larger batches are clamped to it.
+func (connect *DataBaseConnector) insertRowsChunked(ctx context.Context, table string, columns []string, rows [][]interface{}, batchSize int) (int64, error) {
+	limit := maxRowsPerStatement(connect.dbType, len(columns))
+	if batchSize <= 0 || batchSize > limit {
+		batchSize = limit
+	}
+
+	var total int64
+	err := connect.WithTransaction(ctx, nil, func(tx *sql.Tx) error {
+		for _, bounds := range chunkRanges(len(rows), batchSize) {
+			qb := BuildInsert(connect.dbType, table).ValuesRows(columns, rows[bounds[0]:bounds[1]])
+			query, args, err := qb.Build()
+			qb.Release()
+			if err != nil {
+				return err
+			}
+
+			result, err := tx.ExecContext(ctx, query, args...)
+			if err != nil {
+				return fmt.Errorf("exec batch insert error: %w", err)
+			}
+			affected, err := result.RowsAffected()
+			if err != nil {
+				return fmt.Errorf("rows affected error: %w", err)
+			}
+			total += affected
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+/*
+InsertBatched
+
+@ conn: Database connection
+@ table: Target table
+@ rows: Rows to insert, every row with the same columns
+@ batchSize: Rows per INSERT statement, clamped to the database bind parameter limit (<= 0 uses the limit)
+@ Return: Total rows affected and error if any
+
+All batches run in a single transaction, so either every row is inserted or none is.
+*/
+func InsertBatched(conn *DataBaseConnector, table string, rows []map[string]interface{}, batchSize int) (int64, error) {
+	if len(rows) == 0 {
+		return 0, nil
+	}
+
+	columns := sortedKeys(rows[0])
+	if len(columns) == 0 {
+		return 0, fmt.Errorf("batch insert %w", ErrNoDataProvided)
+	}
+
+	values := make([][]interface{}, len(rows))
+	for i, row := range rows {
+		if len(row) != len(columns) {
+			return 0, fmt.Errorf("batch insert row %d has %d columns, expected %d", i, len(row), len(columns))
+		}
+		values[i] = make([]interface{}, len(columns))
+		for j, col := range columns {
+			val, ok := row[col]
+			if !ok {
+				return 0, fmt.Errorf("batch insert row %d is missing column %s", i, col)
+			}
+			values[i][j] = val
+		}
+	}
+
+	return conn.insertRowsChunked(context.Background(), table, columns, values, batchSize)
+}
+
+/*
+DeleteByIds
+
+@ conn: Database connection
+@ table: Target table
+@ idColumn: Primary key column
+@ ids: Primary keys of the rows to delete
+@ Return: Total rows affected and error if any
+
+Keys are deleted with DELETE ... WHERE idColumn IN (...) statements sized to the bind parameter limit,
+all in a single transaction. No statement is executed when ids is empty.
+*/
+func DeleteByIds(conn *DataBaseConnector, table, idColumn string, ids []interface{}) (int64, error) {
+	return conn.deleteIdsChunked(context.Background(), table, idColumn, ids, maxRowsPerStatement(conn.dbType, 1))
+}
+
+// deleteIdsChunked deletes ids with one DELETE statement per chunk of at most chunkSize keys in a single transaction.
+// Keys are bound by the connection like any other argument, so time keys follow DBConfig.TimeFormat.
+func (connect *DataBaseConnector) deleteIdsChunked(ctx context.Context, table, idColumn string, ids []interface{}, chunkSize int) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	var total int64
+	err := connect.WithTransaction(ctx, nil, func(tx *sql.Tx) error {
+		for _, bounds := range chunkRanges(len(ids), chunkSize) {
+			qb := BuildDelete(connect.dbType, table).WhereIn(idColumn, ids[bounds[0]:bounds[1]])
+			query, args, err := qb.Build()
+			qb.Release()
+			if err != nil {
+				return err
+			}
+
+			result, err := tx.ExecContext(ctx, query, args...)
+			if err != nil {
+				return fmt.Errorf("exec batch delete error: %w", err)
+			}
+			affected, err := result.RowsAffected()
+			if err != nil {
+				return fmt.Errorf("rows affected error: %w", err)
+			}
+			total += affected
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+/*
+BulkUpdate
+
+@ conn: Database connection
+@ table: Target table
+@ keyColumn: Primary key column identifying the rows
+@ updates: New column values keyed by the primary key of each row
+@ Return: Rows affected and error if any
+
+Rows are updated with one CASE per column:
+UPDATE table SET col = CASE keyColumn WHEN ? THEN ? ... ELSE col END WHERE keyColumn IN (...).
+Keys are split into as many statements as the bind parameter limit of the database requires,
+all run in a single transaction.
+Rows may set different columns; a row keeps the columns it does not set.
+MariaDB/MySQL count only rows whose values actually changed.
+*/
+func BulkUpdate(conn *DataBaseConnector, table, keyColumn string, updates map[interface{}]map[string]interface{}) (int64, error) {
+	columns := make(map[string]interface{})
+	for _, row := range updates {
+		for col := range row {
+			columns[col] = nil
+		}
+	}
+	// Each key binds a WHEN ? THEN ? pair per column and one WHERE IN parameter
+	chunkSize := maxRowsPerStatement(conn.dbType, 2*len(columns)+1)
+	return conn.bulkUpdateChunked(context.Background(), table, keyColumn, updates, chunkSize)
+}
+
+// bulkUpdateChunked runs BulkUpdate with one UPDATE statement per chunk of at most chunkSize keys in a single transaction.
+func (connect *DataBaseConnector) bulkUpdateChunked(ctx context.Context, table, keyColumn string, updates map[interface{}]map[string]interface{}, chunkSize int) (int64, error) {
+	if len(updates) == 0 {
+		return 0, nil
+	}
+	keys := sortedUpdateKeys(updates)
+
+	var total int64
+	err := connect.WithTransaction(ctx, nil, func(tx *sql.Tx) error {
+		for _, bounds := range chunkRanges(len(keys), chunkSize) {
+			chunk := make(map[interface{}]map[string]interface{}, bounds[1]-bounds[0])
+			for _, key := range keys[bounds[0]:bounds[1]] {
+				chunk[key] = updates[key]
+			}
+			affected, err := bulkUpdateBuilder(connect.dbType, table, keyColumn, chunk).UpdateAffected(tx)
+			if err != nil {
+				return err
+			}
+			total += affected
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+// sortedUpdateKeys returns the BulkUpdate keys in a stable order.
+func sortedUpdateKeys(updates map[interface{}]map[string]interface{}) []interface{} {
+	keys := make([]interface{}, 0, len(updates))
+	for key := range updates {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j])
+	})
+	return keys
+}
+
+// bulkUpdateBuilder builds the CASE statement of BulkUpdate with keys and columns in sorted order.
+func bulkUpdateBuilder(dbType DBType, table, keyColumn string, updates map[interface{}]map[string]interface{}) *QueryBuilder {
+	qb := BuildUpdate(dbType, table)
+	if qb.err != nil {
+		return qb
+	}
+	safeKey, err := qb.dialect.EscapeIdentifier(keyColumn)
+	if err != nil {
+		qb.err = err
+		return qb
+	}
+
+	keys := sortedUpdateKeys(updates)
+	columnSet := make(map[string]interface{})
+	for _, key := range keys {
+		row := updates[key]
+		if len(row) == 0 {
+			qb.err = fmt.Errorf("bulk update key %v: %w", key, ErrNoDataProvided)
+			return qb
+		}
+		for col := range row {
+			columnSet[col] = nil
+		}
+	}
+
+	for _, col := range sortedKeys(columnSet) {
+		safeCol, err := qb.dialect.EscapeIdentifier(col)
+		if err != nil {
+			qb.err = err
+			return qb
+		}
+
+		var expr strings.Builder
+		var args []interface{}
+		expr.WriteString("CASE " + safeKey)
+		for _, key := range keys {
+			val, ok := updates[key][col]
+			if !ok {
+				continue
+			}
+			expr.WriteString(" WHEN ? THEN ")
+			args = append(args, key)
+			if raw, ok := val.(Raw); ok {
+				expr.WriteString(string(raw))
+			} else {
+				expr.WriteString("?")
+				args = append(args, val)
+			}
+		}
+		expr.WriteString(" ELSE " + safeCol + " END")
+		qb.SetRaw(col, expr.String(), args...)
+	}
+
+	return qb.WhereIn(keyColumn, keys)
+}
+
+// chunkRanges splits n items into [start, end) ranges of at most size items.
+func chunkRanges(n, size int) [][2]int {
+	ranges := make([][2]int, 0, (n+size-1)/size)
+	for start := 0; start < n; start += size {
+		end := start + size
+		if end > n {
+			end = n
+		}
+		ranges = append(ranges, [2]int{start, end})
+	}
+	return ranges
+}
+
+// maxRowsPerStatement returns how many rows of the given width fit in one statement.
+func maxRowsPerStatement(dbType DBType, columnCount int) int {
+	limit, ok := maxBindParams[dbType]
+	if !ok {
+		limit = maxBindParams[Sqlite]
+	}
+	rows := limit / columnCount
+	if rows < 1 {
+		rows = 1
+	}
+	return rows
+}
@@ -0,0 +1,337 @@
+package gdct
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestValuesRows(t *testing.T) {
+	query, args, err := BuildInsert(PostgreSQL, "users").
+		ValuesRows([]string{"name", "age"}, [][]interface{}{{"John", 30}, {"Jane", Raw("DEFAULT")}}).
+		Returning("id").
+		Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "INSERT INTO users (name, age) VALUES ($1, $2), ($3, DEFAULT) RETURNING id"
+	if query != expected {
+		t.Errorf("Expected %q, got %q", expected, query)
+	}
+	if len(args) != 3 {
+		t.Errorf("Expected 3 args, got %v", args)
+	}
+
+	_, _, err = BuildInsert(PostgreSQL, "users").ValuesRows([]string{"name", "age"}, [][]interface{}{{"John"}}).Build()
+	if err == nil {
+		t.Errorf("Expected error for row with missing values")
+	}
+}
+
+func testRows(n int) [][]interface{} {
+	rows := make([][]interface{}, n)
+	for i := range rows {
+		rows[i] = []interface{}{fmt.Sprintf("user-%d", i), i % 90}
+	}
+	return rows
+}
+
+func TestCopyFromSqlite(t *testing.T) {
+	conn := openTestSqlite(t, DBConfig{})
+	createTestUsers(t, conn)
+
+	loaded, err := conn.CopyFrom("users", []string{"name", "age"}, testRows(700))
+	if err != nil {
+		t.Fatalf("CopyFrom failed: %v", err)
+	}
+	if loaded != 700 {
+		t.Errorf("Expected 700 rows loaded, got %d", loaded)
+	}
+
+	count, err := Count(conn, BuildSelect(Sqlite, "users"))
+	if err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	if count != 700 {
+		t.Errorf("Expected 700 rows, got %d", count)
+	}
+}
+
+func TestCopyFromPostgres(t *testing.T) {
+	cfg, err := ConfigFromEnv("GDCT_TEST_PG")
+	if err != nil {
+		t.Skipf("PostgreSQL test database not configured: %v", err)
+	}
+	conn, err := InitConnection(PostgreSQL, cfg)
+	if err != nil {
+		t.Skipf("PostgreSQL unavailable: %v", err)
+	}
+	defer conn.Close()
+	if err := conn.PgCheckConnection(); err != nil {
+		t.Skipf("PostgreSQL unavailable: %v", err)
+	}
+
+	// Keep the session holding the temporary table
+	conn.SetMaxOpenConns(1)
+	if _, err := conn.Exec("CREATE TEMP TABLE copy_users (name TEXT NOT NULL, age INTEGER NOT NULL)"); err != nil {
+		t.Fatalf("Create table failed: %v", err)
+	}
+
+	loaded, err := conn.CopyFrom("copy_users", []string{"name", "age"}, testRows(300))
+	if err != nil {
+		t.Fatalf("CopyFrom failed: %v", err)
+	}
+	if loaded != 300 {
+		t.Errorf("Expected 300 rows loaded, got %d", loaded)
+	}
+}
+
+func TestChunkRanges(t *testing.T) {
+	tests := []struct {
+		n        int
+		size     int
+		expected [][2]int
+	}{
+		{0, 10, [][2]int{}},
+		{10, 10, [][2]int{{0, 10}}},
+		{25, 10, [][2]int{{0, 10}, {10, 20}, {20, 25}}},
+		{3, 1, [][2]int{{0, 1}, {1, 2}, {2, 3}}},
+	}
+
+	for _, tt := range tests {
+		got := chunkRanges(tt.n, tt.size)
+		if fmt.Sprint(got) != fmt.Sprint(tt.expected) {
+			t.Errorf("chunkRanges(%d, %d) = %v, expected %v", tt.n, tt.size, got, tt.expected)
+		}
+	}
+
+	// SQLite allows 999 parameters, so 3 columns fit 333 rows per statement
+	if got := maxRowsPerStatement(Sqlite, 3); got != 333 {
+		t.Errorf("Expected 333 rows per SQLite statement, got %d", got)
+	}
+	if got := maxRowsPerStatement(Mysql, 3); got != 21845 {
+		t.Errorf("Expected 21845 rows per MySQL statement, got %d", got)
+	}
+}
+
+func TestInsertBatched(t *testing.T) {
+	conn := openTestSqlite(t, DBConfig{})
+	createTestUsers(t, conn)
+
+	rows := make([]map[string]interface{}, 1050)
+	for i := range rows {
+		rows[i] = map[string]interface{}{"name": fmt.Sprintf("user-%d", i), "age": i % 90}
+	}
+
+	// A batch size above the SQLite limit is clamped to 499 rows of two columns
+	total, err := InsertBatched(conn, "users", rows, 5000)
+	if err != nil {
+		t.Fatalf("InsertBatched failed: %v", err)
+	}
+	if total != 1050 {
+		t.Errorf("Expected 1050 rows affected, got %d", total)
+	}
+
+	total, err = InsertBatched(conn, "users", rows[:25], 10)
+	if err != nil {
+		t.Fatalf("InsertBatched failed: %v", err)
+	}
+	if total != 25 {
+		t.Errorf("Expected 25 rows affected, got %d", total)
+	}
+
+	count, err := Count(conn, BuildSelect(Sqlite, "users"))
+	if err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	if count != 1075 {
+		t.Errorf("Expected 1075 rows, got %d", count)
+	}
+
+	_, err = InsertBatched(conn, "users", []map[string]interface{}{{"name": "a"}, {"age": 1}}, 10)
+	if err == nil {
+		t.Errorf("Expected error for rows with different columns")
+	}
+}
+
+func TestDeleteByIds(t *testing.T) {
+	conn := openTestSqlite(t, DBConfig{})
+	createTestUsers(t, conn)
+
+	if _, err := conn.CopyFrom("users", []string{"name", "age"}, testRows(1200)); err != nil {
+		t.Fatalf("CopyFrom failed: %v", err)
+	}
+
+	ids := func(from, to int) []interface{} {
+		list := make([]interface{}, 0, to-from+1)
+		for id := from; id <= to; id++ {
+			list = append(list, id)
+		}
+		return list
+	}
+
+	tests := []struct {
+		name      string
+		ids       []interface{}
+		chunkSize int
+		expected  int64
+	}{
+		{"empty", nil, 3, 0},
+		{"exact chunks", ids(1, 6), 3, 6},
+		{"partial last chunk", ids(7, 13), 3, 7},
+		{"single chunk", ids(14, 15), 3, 2},
+		{"missing ids", ids(1, 20), 4, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deleted, err := conn.deleteIdsChunked(context.Background(), "users", "id", tt.ids, tt.chunkSize)
+			if err != nil {
+				t.Fatalf("deleteIdsChunked failed: %v", err)
+			}
+			if deleted != tt.expected {
+				t.Errorf("Expected %d deleted rows, got %d", tt.expected, deleted)
+			}
+		})
+	}
+
+	// More keys than SQLite allows bind parameters in one statement
+	deleted, err := DeleteByIds(conn, "users", "id", ids(21, 1200))
+	if err != nil {
+		t.Fatalf("DeleteByIds failed: %v", err)
+	}
+	if deleted != 1180 {
+		t.Errorf("Expected 1180 deleted rows, got %d", deleted)
+	}
+
+	deleted, err = DeleteByIds(conn, "users", "id", nil)
+	if err != nil || deleted != 0 {
+		t.Errorf("Expected 0 deleted rows without error, got %d, %v", deleted, err)
+	}
+}
+
+func TestDeleteByIdsTimeKeys(t *testing.T) {
+	conn := openTestSqlite(t, DBConfig{TimeFormat: SqliteTimeFormat})
+	if err := conn.SqCreateTable([]string{"CREATE TABLE readings (taken_at PRIMARY KEY, value INTEGER)"}); err != nil {
+		t.Fatalf("Create table failed: %v", err)
+	}
+
+	base := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	var keys []interface{}
+	for i := 0; i < 5; i++ {
+		takenAt := base.Add(time.Duration(i) * time.Minute)
+		if _, err := conn.Exec("INSERT INTO readings (taken_at, value) VALUES (?, ?)", takenAt, i); err != nil {
+			t.Fatalf("Insert failed: %v", err)
+		}
+		// The same instants in another zone match only when both paths store the same format
+		keys = append(keys, takenAt.In(time.FixedZone("KST", 9*60*60)))
+	}
+
+	deleted, err := conn.deleteIdsChunked(context.Background(), "readings", "taken_at", keys[:4], 3)
+	if err != nil {
+		t.Fatalf("deleteIdsChunked failed: %v", err)
+	}
+	if deleted != 4 {
+		t.Errorf("Expected 4 deleted rows, got %d", deleted)
+	}
+}
+
+func TestBulkUpdate(t *testing.T) {
+	updates := map[interface{}]map[string]interface{}{
+		1: {"name": "Ann", "age": 21},
+		2: {"name": "Bob"},
+		3: {"age": Raw("age + 1")},
+	}
+
+	query, args, err := bulkUpdateBuilder(PostgreSQL, "users", "id", updates).Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := "UPDATE users SET age = CASE id WHEN $1 THEN $2 WHEN $3 THEN age + 1 ELSE age END, " +
+		"name = CASE id WHEN $4 THEN $5 WHEN $6 THEN $7 ELSE name END WHERE id IN ($8, $9, $10)"
+	if query != expected {
+		t.Errorf("Expected %q, got %q", expected, query)
+	}
+	if fmt.Sprint(args) != "[1 21 3 1 Ann 2 Bob 1 2 3]" {
+		t.Errorf("Expected args [1 21 3 1 Ann 2 Bob 1 2 3], got %v", args)
+	}
+
+	conn := openTestSqlite(t, DBConfig{})
+	createTestUsers(t, conn)
+	if _, err := conn.CopyFrom("users", []string{"name", "age"}, [][]interface{}{{"a", 10}, {"b", 20}, {"c", 30}, {"d", 40}}); err != nil {
+		t.Fatalf("CopyFrom failed: %v", err)
+	}
+
+	affected, err := BulkUpdate(conn, "users", "id", updates)
+	if err != nil {
+		t.Fatalf("BulkUpdate failed: %v", err)
+	}
+	if affected != 3 {
+		t.Errorf("Expected 3 rows affected, got %d", affected)
+	}
+
+	users, err := SelectAll[testUser](conn, BuildSelect(Sqlite, "users", "id", "name", "age").OrderBy("id", "ASC", map[string]bool{"id": true}))
+	if err != nil {
+		t.Fatalf("SelectAll failed: %v", err)
+	}
+	if fmt.Sprint(users) != "[{1 Ann 21} {2 Bob 20} {3 c 31} {4 d 40}]" {
+		t.Errorf("Unexpected rows after bulk update: %v", users)
+	}
+
+	// Keys split over several statements are updated in one transaction
+	chunked := map[interface{}]map[string]interface{}{
+		1: {"age": 50}, 2: {"age": 51}, 3: {"name": "Cid"}, 4: {"age": 53},
+	}
+	affected, err = conn.bulkUpdateChunked(context.Background(), "users", "id", chunked, 3)
+	if err != nil {
+		t.Fatalf("bulkUpdateChunked failed: %v", err)
+	}
+	if affected != 4 {
+		t.Errorf("Expected 4 rows affected, got %d", affected)
+	}
+	users, err = SelectAll[testUser](conn, BuildSelect(Sqlite, "users", "id", "name", "age").OrderBy("id", "ASC", map[string]bool{"id": true}))
+	if err != nil {
+		t.Fatalf("SelectAll failed: %v", err)
+	}
+	if fmt.Sprint(users) != "[{1 Ann 50} {2 Bob 51} {3 Cid 31} {4 d 53}]" {
+		t.Errorf("Unexpected rows after chunked bulk update: %v", users)
+	}
+
+	// A failing chunk rolls back the chunks before it
+	failing := map[interface{}]map[string]interface{}{1: {"age": 60}, 2: {"age": 61}, 3: {"missing": 1}}
+	if _, err := conn.bulkUpdateChunked(context.Background(), "users", "id", failing, 2); err == nil {
+		t.Errorf("Expected error for an unknown column")
+	}
+	var age int
+	if err := conn.QueryRow("SELECT age FROM users WHERE id = ?", 1).Scan(&age); err != nil {
+		t.Fatalf("QueryRow failed: %v", err)
+	}
+	if age != 50 {
+		t.Errorf("Expected the first chunk rolled back to age 50, got %d", age)
+	}
+
+	// More keys than SQLite allows bind parameters in one statement
+	many := make(map[interface{}]map[string]interface{})
+	for id := 5; id <= 604; id++ {
+		many[id] = map[string]interface{}{"name": fmt.Sprintf("user%d", id), "age": id}
+	}
+	if _, err := conn.CopyFrom("users", []string{"name", "age"}, testRows(600)); err != nil {
+		t.Fatalf("CopyFrom failed: %v", err)
+	}
+	affected, err = BulkUpdate(conn, "users", "id", many)
+	if err != nil {
+		t.Fatalf("BulkUpdate over the bind parameter limit failed: %v", err)
+	}
+	if affected != 600 {
+		t.Errorf("Expected 600 rows affected, got %d", affected)
+	}
+
+	if _, err := BulkUpdate(conn, "users", "id", map[interface{}]map[string]interface{}{1: {}}); err == nil {
+		t.Errorf("Expected error for a row without columns")
+	}
+	if affected, err := BulkUpdate(conn, "users", "id", nil); err != nil || affected != 0 {
+		t.Errorf("Expected 0 rows without error, got %d, %v", affected, err)
+	}
+}
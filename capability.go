@@ -0,0 +1,68 @@
+package gdct
+
+// Feature is an optional SQL capability that only some databases provide.
+type Feature string
+
+const (
+	FeatureReturning          Feature = "returning"           // INSERT ... RETURNING
+	FeatureOnConflict         Feature = "on_conflict"         // INSERT ... ON CONFLICT (columns)
+	FeatureConflictConstraint Feature = "conflict_constraint" // INSERT ... ON CONFLICT ON CONSTRAINT name
+	FeatureJSONTable          Feature = "json_table"          // JSON_TABLE table function
+	FeatureJSONB              Feature = "jsonb"               // jsonb type and functions
+	FeatureLateral            Feature = "lateral"             // JOIN LATERAL derived tables
+	FeatureReplace            Feature = "replace"             // REPLACE INTO
+	FeatureNullsOrder         Feature = "nulls_order"         // ORDER BY ... NULLS FIRST / NULLS LAST
+	FeatureFullText           Feature = "full_text"           // Full-text search on regular tables
+	FeatureTop                Feature = "top"                 // SELECT TOP n
+	FeatureWindowFunctions    Feature = "window_functions"    // Aggregates with OVER()
+	FeatureExplain            Feature = "explain"             // EXPLAIN statement prefix
+)
+
+// capabilities lists the optional features of each built-in database type.
+// MariaDB and MySQL share a driver and dialect but differ here (RETURNING in MariaDB 10.5+, LATERAL in MySQL 8.0.14+).
+// Window functions need MariaDB 10.2+, MySQL 8.0+ and SQLite 3.25+.
+var capabilities = map[DBType]map[Feature]bool{
+	PostgreSQL: {
+		FeatureReturning:          true,
+		FeatureOnConflict:         true,
+		FeatureConflictConstraint: true,
+		FeatureJSONB:              true,
+		FeatureLateral:            true,
+		FeatureNullsOrder:         true,
+		FeatureFullText:           true,
+		FeatureWindowFunctions:    true,
+		FeatureExplain:            true,
+	},
+	MariaDB: {
+		FeatureReturning:       true,
+		FeatureJSONTable:       true,
+		FeatureReplace:         true,
+		FeatureFullText:        true,
+		FeatureWindowFunctions: true,
+		FeatureExplain:         true,
+	},
+	Mysql: {
+		FeatureJSONTable:       true,
+		FeatureLateral:         true,
+		FeatureReplace:         true,
+		FeatureFullText:        true,
+		FeatureWindowFunctions: true,
+		FeatureExplain:         true,
+	},
+	Sqlite: {
+		FeatureReturning:       true,
+		FeatureOnConflict:      true,
+		FeatureNullsOrder:      true,
+		FeatureWindowFunctions: true,
+		FeatureExplain:         true,
+	},
+	SQLServer: {
+		FeatureTop:             true,
+		FeatureWindowFunctions: true,
+	},
+}
+
+// Supports reports whether the database type provides the given feature.
+func (d DBType) Supports(feature Feature) bool {
+	return capabilities[d][feature]
+}
@@ -0,0 +1,51 @@
+package gdct
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSupports(t *testing.T) {
+	tests := []struct {
+		dbType   DBType
+		feature  Feature
+		expected bool
+	}{
+		{PostgreSQL, FeatureReturning, true},
+		{MariaDB, FeatureReturning, true},
+		{Mysql, FeatureReturning, false},
+		{Sqlite, FeatureReturning, true},
+		{Mysql, FeatureJSONTable, true},
+		{PostgreSQL, FeatureJSONTable, false},
+		{DBType("oracle"), FeatureReturning, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.dbType.Supports(tt.feature); got != tt.expected {
+			t.Errorf("%s.Supports(%s) = %v, expected %v", tt.dbType, tt.feature, got, tt.expected)
+		}
+	}
+}
+
+func TestReturningCapability(t *testing.T) {
+	query, _, err := BuildInsert(MariaDB, "users").
+		Values(map[string]interface{}{"name": "John"}).
+		Returning("id").
+		Build()
+	if err != nil {
+		t.Fatalf("Unexpected error for MariaDB: %v", err)
+	}
+
+	expected := "INSERT INTO users (name) VALUES (?) RETURNING id"
+	if query != expected {
+		t.Errorf("Expected %q, got %q", expected, query)
+	}
+
+	_, _, err = BuildInsert(Mysql, "users").
+		Values(map[string]interface{}{"name": "John"}).
+		Returning("id").
+		Build()
+	if !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Expected ErrUnsupported for Mysql, got %v", err)
+	}
+}
@@ -0,0 +1,101 @@
+package gdct
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// castTypeRegexp matches a type name with an optional length or precision, e.g. "varchar(255)" or "decimal(10, 2)".
+var castTypeRegexp = regexp.MustCompile(`^([A-Za-z][A-Za-z ]*?)\s*(\(\s*\d+\s*(?:,\s*\d+\s*)?\))?$`)
+
+// castTypes maps common type names to the names each database accepts in CAST.
+// MariaDB and Mysql only cast to CHAR, SIGNED, DECIMAL, DOUBLE and the date types.
+var castTypes = map[string]map[DBType]string{
+	"text":      {PostgreSQL: "TEXT", MariaDB: "CHAR", Mysql: "CHAR", Sqlite: "TEXT"},
+	"varchar":   {PostgreSQL: "VARCHAR", MariaDB: "CHAR", Mysql: "CHAR", Sqlite: "TEXT"},
+	"char":      {PostgreSQL: "CHAR", MariaDB: "CHAR", Mysql: "CHAR", Sqlite: "TEXT"},
+	"int":       {PostgreSQL: "INTEGER", MariaDB: "SIGNED", Mysql: "SIGNED", Sqlite: "INTEGER"},
+	"integer":   {PostgreSQL: "INTEGER", MariaDB: "SIGNED", Mysql: "SIGNED", Sqlite: "INTEGER"},
+	"bigint":    {PostgreSQL: "BIGINT", MariaDB: "SIGNED", Mysql: "SIGNED", Sqlite: "INTEGER"},
+	"float":     {PostgreSQL: "DOUBLE PRECISION", MariaDB: "DOUBLE", Mysql: "DOUBLE", Sqlite: "REAL"},
+	"double":    {PostgreSQL: "DOUBLE PRECISION", MariaDB: "DOUBLE", Mysql: "DOUBLE", Sqlite: "REAL"},
+	"real":      {PostgreSQL: "REAL", MariaDB: "DOUBLE", Mysql: "DOUBLE", Sqlite: "REAL"},
+	"decimal":   {PostgreSQL: "DECIMAL", MariaDB: "DECIMAL", Mysql: "DECIMAL", Sqlite: "NUMERIC"},
+	"numeric":   {PostgreSQL: "NUMERIC", MariaDB: "DECIMAL", Mysql: "DECIMAL", Sqlite: "NUMERIC"},
+	"date":      {PostgreSQL: "DATE", MariaDB: "DATE", Mysql: "DATE", Sqlite: "TEXT"},
+	"datetime":  {PostgreSQL: "TIMESTAMP", MariaDB: "DATETIME", Mysql: "DATETIME", Sqlite: "TEXT"},
+	"timestamp": {PostgreSQL: "TIMESTAMP", MariaDB: "DATETIME", Mysql: "DATETIME", Sqlite: "TEXT"},
+}
+
+/*
+Cast
+
+@ dbType: Database type the expression is written for
+@ expr: SQL expression to convert, written literally
+@ sqlType: Target type, e.g. "text", "integer" or "decimal(10, 2)"
+@ Return: CAST(expr AS type) as a Raw expression and error if the type is malformed
+
+Common type names are normalized for dbType: "text" becomes CHAR and "integer" becomes SIGNED on MariaDB and Mysql.
+Other names are passed through upper-cased. Never build expr from user input.
+*/
+func Cast(dbType DBType, expr, sqlType string) (Raw, error) {
+	if strings.TrimSpace(expr) == "" {
+		return "", fmt.Errorf("cast expression cannot be empty")
+	}
+	castType, err := normalizeCastType(dbType, sqlType)
+	if err != nil {
+		return "", err
+	}
+	return Raw("CAST(" + expr + " AS " + castType + ")"), nil
+}
+
+// normalizeCastType maps sqlType to the name dbType accepts, keeping any length or precision.
+func normalizeCastType(dbType DBType, sqlType string) (string, error) {
+	match := castTypeRegexp.FindStringSubmatch(strings.TrimSpace(sqlType))
+	if match == nil {
+		return "", fmt.Errorf("invalid cast type: %q", sqlType)
+	}
+
+	name := strings.Join(strings.Fields(strings.ToLower(match[1])), " ")
+	size := strings.ReplaceAll(match[2], " ", "")
+
+	castType := strings.ToUpper(name)
+	if mapped, ok := castTypes[name][dbType]; ok {
+		castType = mapped
+	}
+	// SIGNED, DOUBLE, REAL and INTEGER take no length; SQLite ignores it entirely
+	switch castType {
+	case "SIGNED", "DOUBLE", "DOUBLE PRECISION", "REAL", "INTEGER", "BIGINT", "TEXT":
+		size = ""
+	}
+	return castType + size, nil
+}
+
+/*
+SelectCast
+
+@ column: Column to convert
+@ sqlType: Target type, normalized as in Cast
+@ alias: Alias of the converted column
+@ Return: *QueryBuilder with CAST(column AS type) AS alias selected
+*/
+func (qb *QueryBuilder) SelectCast(column, sqlType, alias string) *QueryBuilder {
+	if qb.err != nil {
+		return qb
+	}
+	if !qb.checkColumns(column) {
+		return qb
+	}
+	safeCol, err := qb.dialect.EscapeIdentifier(column)
+	if err != nil {
+		qb.err = err
+		return qb
+	}
+	castExpr, err := Cast(qb.dbType, safeCol, sqlType)
+	if err != nil {
+		qb.err = err
+		return qb
+	}
+	return qb.selectAs(string(castExpr), alias)
+}
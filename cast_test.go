@@ -0,0 +1,64 @@
+package gdct
+
+import "testing"
+
+func TestCast(t *testing.T) {
+	tests := []struct {
+		name     string
+		dbType   DBType
+		sqlType  string
+		expected Raw
+	}{
+		{"postgres text", PostgreSQL, "text", "CAST(price AS TEXT)"},
+		{"mysql text", Mysql, "text", "CAST(price AS CHAR)"},
+		{"mariadb varchar length", MariaDB, "varchar(20)", "CAST(price AS CHAR(20))"},
+		{"postgres int", PostgreSQL, "int", "CAST(price AS INTEGER)"},
+		{"mysql integer", Mysql, "INTEGER", "CAST(price AS SIGNED)"},
+		{"sqlite varchar", Sqlite, "varchar(255)", "CAST(price AS TEXT)"},
+		{"decimal precision", PostgreSQL, "decimal(10, 2)", "CAST(price AS DECIMAL(10,2))"},
+		{"unknown type", PostgreSQL, "uuid", "CAST(price AS UUID)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Cast(tt.dbType, "price", tt.sqlType)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+
+	for _, sqlType := range []string{"", "text); DROP TABLE users; --", "int(x)"} {
+		if _, err := Cast(PostgreSQL, "price", sqlType); err == nil {
+			t.Errorf("Expected error for cast type %q", sqlType)
+		}
+	}
+}
+
+func TestSelectCast(t *testing.T) {
+	tests := []struct {
+		dbType        DBType
+		expectedQuery string
+	}{
+		{PostgreSQL, "SELECT id, CAST(price AS INTEGER) AS price_int FROM products"},
+		{Mysql, "SELECT id, CAST(price AS SIGNED) AS price_int FROM products"},
+		{Sqlite, "SELECT id, CAST(price AS INTEGER) AS price_int FROM products"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dbType.String(), func(t *testing.T) {
+			query, _, err := BuildSelect(tt.dbType, "products", "id").
+				SelectCast("price", "integer", "price_int").
+				Build()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if query != tt.expectedQuery {
+				t.Errorf("Expected %q, got %q", tt.expectedQuery, query)
+			}
+		})
+	}
+}
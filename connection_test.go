@@ -1,10 +1,30 @@
 package gdct
 
 import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
 	"testing"
 	"time"
 )
 
+// openTestSqlite opens a SQLite connection backed by a temporary database file.
+func openTestSqlite(t *testing.T, cfg DBConfig) *DataBaseConnector {
+	t.Helper()
+
+	cfg.Database = filepath.Join(t.TempDir(), "test.sqlite")
+
+	conn, connErr := InitConnection(Sqlite, cfg)
+	if connErr != nil {
+		t.Fatalf("[SQLITE_OPEN] Create Connection Test Error: %v", connErr)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return conn
+}
+
 func TestCheckPostTest(t *testing.T) {
 	sslMode := "disable" // Only Postgres
 
@@ -86,3 +106,276 @@ func TestCheckMysqlTest(t *testing.T) {
 		t.Fatalf("[MYSQL_CHECK] Connection Test Error: %v", pingErr)
 	}
 }
+
+func TestCheckConnectionContextCancelled(t *testing.T) {
+	conn := openTestSqlite(t, DBConfig{})
+
+	if err := conn.CheckConnectionContext(context.Background()); err != nil {
+		t.Fatalf("[SQLITE_CHECK] Connection Test Error: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	start := time.Now()
+	err := conn.CheckConnectionContext(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected prompt error, took %s", elapsed)
+	}
+}
+
+func TestSqliteTypedArgs(t *testing.T) {
+	conn := openTestSqlite(t, DBConfig{})
+
+	if err := conn.SqCreateTable([]string{
+		"CREATE TABLE items (id INTEGER PRIMARY KEY, qty INTEGER, active BOOLEAN, data BLOB)",
+	}); err != nil {
+		t.Fatalf("SqCreateTable error: %v", err)
+	}
+
+	blob := []byte{0x00, 0xff, 0x10}
+	if _, err := conn.SqInsertQuery(
+		"INSERT INTO items (qty, active, data) VALUES (?, ?, ?)",
+		[]interface{}{42, true, blob},
+	); err != nil {
+		t.Fatalf("SqInsertQuery error: %v", err)
+	}
+
+	row, err := conn.SqSelectSingle("SELECT qty, active, data FROM items WHERE qty = ?", []interface{}{42})
+	if err != nil {
+		t.Fatalf("SqSelectSingle error: %v", err)
+	}
+
+	var qty int
+	var active bool
+	var data []byte
+	if err := row.Scan(&qty, &active, &data); err != nil {
+		t.Fatalf("Scan error: %v", err)
+	}
+	if qty != 42 || !active || !bytes.Equal(data, blob) {
+		t.Errorf("Expected (42, true, %v), got (%d, %v, %v)", blob, qty, active, data)
+	}
+}
+
+func TestHelperArguments(t *testing.T) {
+	conn := openTestSqlite(t, DBConfig{})
+	if err := conn.SqCreateTable([]string{"CREATE TABLE items (id INTEGER PRIMARY KEY, name TEXT NOT NULL, qty INTEGER, price REAL, payload BLOB, note TEXT)"}); err != nil {
+		t.Fatalf("SqCreateTable error: %v", err)
+	}
+
+	// The helpers of every dialect take the same []interface{} arguments, so each set runs against SQLite
+	helpers := []struct {
+		name       string
+		insert     func(string, []interface{}) (sql.Result, error)
+		update     func(string, []interface{}) (sql.Result, error)
+		selectRows func(string, []interface{}) (*sql.Rows, error)
+		remove     func(string, []interface{}) (sql.Result, error)
+	}{
+		{
+			name: "postgres",
+			insert: func(query string, args []interface{}) (sql.Result, error) {
+				return conn.PgInsertQuery(query, nil, args)
+			},
+			update:     conn.PgUpdateQuery,
+			selectRows: conn.PgSelectMultiple,
+			remove:     conn.PgDeleteQuery,
+		},
+		{"mariadb", conn.MrInsertQuery, conn.MrUpdateQuery, conn.MrSelectMultiple, conn.MrDeleteQuery},
+		{"sqlite", conn.SqInsertQuery, conn.SqUpdateQuery, conn.SqSelectMultiple, conn.SqDeleteQuery},
+	}
+
+	for _, h := range helpers {
+		t.Run(h.name, func(t *testing.T) {
+			if _, err := h.insert("INSERT INTO items (name, qty, price, payload, note) VALUES (?, ?, ?, ?, ?)",
+				[]interface{}{h.name, 3, 1.5, []byte{0x01, 0x02}, nil}); err != nil {
+				t.Fatalf("Insert error: %v", err)
+			}
+			if _, err := h.update("UPDATE items SET qty = qty + ?, note = ? WHERE name = ?",
+				[]interface{}{int64(2), "updated", h.name}); err != nil {
+				t.Fatalf("Update error: %v", err)
+			}
+
+			rows, err := h.selectRows("SELECT qty, price, payload, note FROM items WHERE name = ? AND price > ?",
+				[]interface{}{h.name, float32(1)})
+			if err != nil {
+				t.Fatalf("Select error: %v", err)
+			}
+			defer rows.Close()
+
+			count := 0
+			for rows.Next() {
+				var qty int
+				var price float64
+				var payload []byte
+				var note sql.NullString
+				if err := rows.Scan(&qty, &price, &payload, &note); err != nil {
+					t.Fatalf("Scan error: %v", err)
+				}
+				if qty != 5 || price != 1.5 || !bytes.Equal(payload, []byte{0x01, 0x02}) || note.String != "updated" {
+					t.Errorf("Unexpected row: qty=%d price=%v payload=%v note=%v", qty, price, payload, note)
+				}
+				count++
+			}
+			if err := rows.Err(); err != nil {
+				t.Fatalf("Rows error: %v", err)
+			}
+			if count != 1 {
+				t.Errorf("Expected 1 row, got %d", count)
+			}
+
+			result, err := h.remove("DELETE FROM items WHERE name = ? AND qty = ?", []interface{}{h.name, uint8(5)})
+			if err != nil {
+				t.Fatalf("Delete error: %v", err)
+			}
+			if affected, _ := result.RowsAffected(); affected != 1 {
+				t.Errorf("Expected 1 deleted row, got %d", affected)
+			}
+		})
+	}
+}
+
+func TestSqliteTimeArgs(t *testing.T) {
+	base := time.Date(2024, 3, 1, 9, 0, 0, 0, time.FixedZone("KST", 9*60*60))
+
+	for _, format := range []string{SqliteTimeFormat, TimeFormatUnix} {
+		t.Run("format "+format, func(t *testing.T) {
+			conn := openTestSqlite(t, DBConfig{TimeFormat: format})
+
+			if err := conn.SqCreateTable([]string{"CREATE TABLE events (id INTEGER PRIMARY KEY, at)"}); err != nil {
+				t.Fatalf("SqCreateTable error: %v", err)
+			}
+
+			for _, offset := range []time.Duration{-time.Hour, 0, 30 * time.Minute, 2 * time.Hour} {
+				if _, err := conn.SqInsertQuery("INSERT INTO events (at) VALUES (?)", []interface{}{base.Add(offset)}); err != nil {
+					t.Fatalf("SqInsertQuery error: %v", err)
+				}
+			}
+
+			// The range mixes time zones: both bounds name the same instants as base and base+1h
+			row, err := conn.SqSelectSingle(
+				"SELECT COUNT(*) FROM events WHERE at >= ? AND at < ?",
+				[]interface{}{base.UTC(), base.Add(time.Hour)},
+			)
+			if err != nil {
+				t.Fatalf("SqSelectSingle error: %v", err)
+			}
+
+			var count int
+			if err := row.Scan(&count); err != nil {
+				t.Fatalf("Scan error: %v", err)
+			}
+			if count != 2 {
+				t.Errorf("Expected 2 events in range, got %d", count)
+			}
+		})
+	}
+}
+
+func TestSqliteTimeFormatPaths(t *testing.T) {
+	at := time.Date(2024, 3, 1, 9, 0, 0, 0, time.FixedZone("KST", 9*60*60))
+	ctx := context.Background()
+
+	for _, format := range []string{SqliteTimeFormat, TimeFormatUnix} {
+		t.Run("format "+format, func(t *testing.T) {
+			conn := openTestSqlite(t, DBConfig{TimeFormat: format})
+			if err := conn.SqCreateTable([]string{"CREATE TABLE events (id INTEGER PRIMARY KEY, source TEXT, at)"}); err != nil {
+				t.Fatalf("SqCreateTable error: %v", err)
+			}
+
+			if _, err := conn.ExecBuilder(BuildInsert(Sqlite, "events").Values(map[string]interface{}{"source": "builder", "at": at})); err != nil {
+				t.Fatalf("ExecBuilder error: %v", err)
+			}
+			if _, err := InsertBatched(conn, "events", []map[string]interface{}{{"source": "batched", "at": &at}}, 10); err != nil {
+				t.Fatalf("InsertBatched error: %v", err)
+			}
+			if err := conn.WithTransaction(ctx, nil, func(tx *sql.Tx) error {
+				_, err := tx.ExecContext(ctx, "INSERT INTO events (source, at) VALUES (?, ?)", "transaction", at)
+				return err
+			}); err != nil {
+				t.Fatalf("WithTransaction error: %v", err)
+			}
+			if _, err := conn.ExecMultipleTx(ctx, nil, []PreparedQuery{
+				{Query: "INSERT INTO events (source, at) VALUES (?, ?)", Params: []interface{}{"prepared", at.UTC()}},
+			}); err != nil {
+				t.Fatalf("ExecMultipleTx error: %v", err)
+			}
+
+			var total, distinct int
+			if err := conn.QueryRow("SELECT COUNT(*), COUNT(DISTINCT at) FROM events WHERE at >= ? AND at < ?",
+				at.Add(-time.Minute), at.Add(time.Minute)).Scan(&total, &distinct); err != nil {
+				t.Fatalf("QueryRow error: %v", err)
+			}
+			if total != 4 || distinct != 1 {
+				t.Errorf("Expected 4 rows sharing one stored value, got %d rows with %d values", total, distinct)
+			}
+		})
+	}
+}
+
+func TestSqliteTimeFormatOptIn(t *testing.T) {
+	conn := openTestSqlite(t, DBConfig{})
+	if err := conn.SqCreateTable([]string{"CREATE TABLE events (id INTEGER PRIMARY KEY, at)"}); err != nil {
+		t.Fatalf("SqCreateTable error: %v", err)
+	}
+
+	at := time.Date(2024, 3, 1, 9, 0, 0, 0, time.FixedZone("KST", 9*60*60))
+	if _, err := conn.Exec("INSERT INTO events (at) VALUES (?)", at); err != nil {
+		t.Fatalf("Exec error: %v", err)
+	}
+
+	// Without a TimeFormat the driver's own layout is stored, as in databases written before TimeFormat existed
+	var stored string
+	if err := conn.QueryRow("SELECT at FROM events").Scan(&stored); err != nil {
+		t.Fatalf("QueryRow error: %v", err)
+	}
+	if expected := at.Format("2006-01-02 15:04:05.999999999-07:00"); stored != expected {
+		t.Errorf("Expected driver format %q, got %q", expected, stored)
+	}
+}
+
+func TestSqliteBoolFilter(t *testing.T) {
+	conn := openTestSqlite(t, DBConfig{})
+
+	if err := conn.SqCreateTable([]string{"CREATE TABLE flags (id INTEGER PRIMARY KEY, enabled BOOLEAN)"}); err != nil {
+		t.Fatalf("SqCreateTable error: %v", err)
+	}
+	for _, enabled := range []interface{}{true, false, true, "true"} {
+		if _, err := conn.SqInsertQuery("INSERT INTO flags (enabled) VALUES (?)", []interface{}{enabled}); err != nil {
+			t.Fatalf("SqInsertQuery error: %v", err)
+		}
+	}
+
+	// The "true" text row is not a boolean and must not match
+	var count int
+	if err := conn.QueryRow("SELECT COUNT(*) FROM flags WHERE enabled = ?", true).Scan(&count); err != nil {
+		t.Fatalf("QueryRow error: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("Expected 2 enabled rows, got %d", count)
+	}
+
+	query, args, err := BuildSelect(Sqlite, "flags", "id").Where("enabled = ?", false).Build()
+	if err != nil {
+		t.Fatalf("Build error: %v", err)
+	}
+	rows, err := conn.QueryBuilderRows(query, args)
+	if err != nil {
+		t.Fatalf("QueryBuilderRows error: %v", err)
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			t.Fatalf("Scan error: %v", err)
+		}
+		ids = append(ids, id)
+	}
+	if len(ids) != 1 || ids[0] != 2 {
+		t.Errorf("Expected disabled row [2], got %v", ids)
+	}
+}
@@ -0,0 +1,128 @@
+package gdct
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+)
+
+// openDB opens a database handle. Every new physical connection is wrapped with wrapConn
+// and then handed to onConnect, each only when it is set.
+func openDB(driverName, dsn string, onConnect func(*sql.Conn) error, wrapConn func(driver.Conn) driver.Conn) (*sql.DB, error) {
+	db, err := sql.Open(driverName, dsn)
+	if err != nil || (onConnect == nil && wrapConn == nil) {
+		return db, err
+	}
+
+	// sql.Open does not connect, so the handle is only used to look up the registered driver
+	drv := db.Driver()
+	db.Close()
+
+	var connector driver.Connector
+	if driverCtx, ok := drv.(driver.DriverContext); ok {
+		if connector, err = driverCtx.OpenConnector(dsn); err != nil {
+			return nil, err
+		}
+	} else {
+		connector = dsnConnector{dsn: dsn, driver: drv}
+	}
+
+	if wrapConn != nil {
+		connector = wrapConnector{Connector: connector, wrap: wrapConn}
+	}
+	if onConnect != nil {
+		connector = hookConnector{Connector: connector, onConnect: onConnect}
+	}
+	return sql.OpenDB(connector), nil
+}
+
+// dsnConnector adapts a driver without driver.DriverContext to driver.Connector.
+type dsnConnector struct {
+	dsn    string
+	driver driver.Driver
+}
+
+func (c dsnConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.dsn)
+}
+
+func (c dsnConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+// wrapConnector wraps each connection opened by the wrapped connector.
+type wrapConnector struct {
+	driver.Connector
+	wrap func(driver.Conn) driver.Conn
+}
+
+func (c wrapConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return c.wrap(conn), nil
+}
+
+// hookConnector runs onConnect on each connection opened by the wrapped connector.
+type hookConnector struct {
+	driver.Connector
+	onConnect func(*sql.Conn) error
+}
+
+func (c hookConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := runOnConnect(ctx, &singleConnector{conn: conn, driver: c.Driver()}, c.onConnect); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("on connect hook error: %w", err)
+	}
+	return conn, nil
+}
+
+// runOnConnect hands a fresh driver connection to the hook as *sql.Conn.
+// The connection is served by a throwaway sql.DB that cannot close it.
+func runOnConnect(ctx context.Context, connector *singleConnector, onConnect func(*sql.Conn) error) error {
+	db := sql.OpenDB(connector)
+	defer db.Close()
+
+	sqlConn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer sqlConn.Close()
+
+	return onConnect(sqlConn)
+}
+
+// singleConnector serves one existing connection a single time.
+type singleConnector struct {
+	conn   driver.Conn
+	driver driver.Driver
+	used   bool
+}
+
+func (c *singleConnector) Connect(context.Context) (driver.Conn, error) {
+	if c.used {
+		return nil, errors.New("on connect hook connection already used")
+	}
+	c.used = true
+	return keepOpenConn{c.conn}, nil
+}
+
+func (c *singleConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+// keepOpenConn ignores Close so that the connection outlives the throwaway sql.DB.
+type keepOpenConn struct {
+	driver.Conn
+}
+
+func (keepOpenConn) Close() error {
+	return nil
+}
@@ -0,0 +1,57 @@
+package gdct
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+)
+
+func TestOnConnect(t *testing.T) {
+	var calls atomic.Int32
+	conn := openTestSqlite(t, DBConfig{OnConnect: func(c *sql.Conn) error {
+		calls.Add(1)
+		_, err := c.ExecContext(context.Background(), "PRAGMA foreign_keys = ON")
+		return err
+	}})
+
+	ctx := context.Background()
+	first, err := conn.Conn(ctx)
+	if err != nil {
+		t.Fatalf("Conn failed: %v", err)
+	}
+	defer first.Close()
+
+	// A second connection while the first is held forces a fresh physical connection
+	second, err := conn.Conn(ctx)
+	if err != nil {
+		t.Fatalf("Conn failed: %v", err)
+	}
+	defer second.Close()
+
+	for i, c := range []*sql.Conn{first, second} {
+		var enabled int
+		if err := c.QueryRowContext(ctx, "PRAGMA foreign_keys").Scan(&enabled); err != nil {
+			t.Fatalf("Read pragma failed: %v", err)
+		}
+		if enabled != 1 {
+			t.Errorf("Connection %d: expected foreign_keys enabled by OnConnect", i)
+		}
+	}
+	if n := calls.Load(); n < 2 {
+		t.Errorf("Expected OnConnect for each connection, got %d calls", n)
+	}
+}
+
+func TestOnConnectError(t *testing.T) {
+	hookErr := errors.New("hook failed")
+	_, err := InitConnection(Sqlite, DBConfig{
+		Database:  filepath.Join(t.TempDir(), "test.sqlite"),
+		OnConnect: func(*sql.Conn) error { return hookErr },
+	})
+	if !errors.Is(err, hookErr) {
+		t.Errorf("Expected hook error, got %v", err)
+	}
+}
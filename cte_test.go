@@ -0,0 +1,71 @@
+package gdct
+
+import "testing"
+
+func TestRecursiveCTE(t *testing.T) {
+	base := BuildSelect(PostgreSQL, "categories", "id").Where("id = ?", 1)
+	recursive := BuildSelect(PostgreSQL, "categories", "categories.id").
+		InnerJoin("tree", "categories.parent_id = tree.id").
+		Where("categories.active = ?", true)
+
+	query, args, err := BuildSelect(PostgreSQL, "tree", "id").
+		RecursiveCTE("tree", base, recursive, true).
+		Where("id > ?", 5).
+		Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "WITH RECURSIVE tree AS (SELECT id FROM categories WHERE id = $1 UNION ALL SELECT categories.id FROM categories INNER JOIN tree ON categories.parent_id = tree.id WHERE categories.active = $2) SELECT id FROM tree WHERE id > $3"
+	if query != expected {
+		t.Errorf("Expected %q, got %q", expected, query)
+	}
+	if len(args) != 3 || args[0] != 1 || args[1] != true || args[2] != 5 {
+		t.Errorf("Expected args [1 true 5], got %v", args)
+	}
+}
+
+func TestRecursiveCTESqlite(t *testing.T) {
+	conn := openTestSqlite(t, DBConfig{})
+	if err := conn.SqCreateTable([]string{"CREATE TABLE categories (id INTEGER PRIMARY KEY, parent_id INTEGER)"}); err != nil {
+		t.Fatalf("Create table failed: %v", err)
+	}
+	if _, err := conn.Exec("INSERT INTO categories (id, parent_id) VALUES (1, NULL), (2, 1), (3, 2), (4, 2), (5, NULL)"); err != nil {
+		t.Fatalf("Insert categories failed: %v", err)
+	}
+
+	base := BuildSelect(Sqlite, "categories", "id").Where("id = ?", 2)
+	recursive := BuildSelect(Sqlite, "categories", "categories.id").
+		InnerJoin("tree", "categories.parent_id = tree.id")
+
+	ids, err := Pluck[int](conn, BuildSelect(Sqlite, "tree").
+		RecursiveCTE("tree", base, recursive, false).
+		OrderBy("id", "ASC", nil), "id")
+	if err != nil {
+		t.Fatalf("Pluck failed: %v", err)
+	}
+	if len(ids) != 3 || ids[0] != 2 || ids[1] != 3 || ids[2] != 4 {
+		t.Errorf("Expected [2 3 4], got %v", ids)
+	}
+}
+
+func TestWith(t *testing.T) {
+	active := BuildSelect(PostgreSQL, "users", "id").Where("active = ?", true)
+
+	query, args, err := BuildSelect(PostgreSQL, "orders", "id").
+		With("active_users", active).
+		Where("user_id IN (SELECT id FROM active_users)").
+		Where("amount > ?", 10).
+		Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "WITH active_users AS (SELECT id FROM users WHERE active = $1) SELECT id FROM orders WHERE user_id IN (SELECT id FROM active_users) AND amount > $2"
+	if query != expected {
+		t.Errorf("Expected %q, got %q", expected, query)
+	}
+	if len(args) != 2 {
+		t.Errorf("Expected 2 args, got %v", args)
+	}
+}
@@ -0,0 +1,71 @@
+package gdct
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+)
+
+/*
+AfterCursor
+
+@ column: Unique, ordered column the pages are keyed on
+@ lastValue: Value of column in the last row of the previous page, nil for the first page
+@ direction: Sort direction, "ASC" or "DESC"
+@ Return: *QueryBuilder seeking past lastValue and ordered by column
+
+Adds column > ? (ASC) or column < ? (DESC) and replaces the ORDER BY clause. Combine with Limit for the page size.
+Unlike OFFSET, the database does not scan the skipped rows.
+*/
+func (qb *QueryBuilder) AfterCursor(column string, lastValue interface{}, direction string) *QueryBuilder {
+	if qb.err != nil {
+		return qb
+	}
+	if qb.op != "SELECT" {
+		qb.err = fmt.Errorf("AfterCursor() can only be used with SELECT queries")
+		return qb
+	}
+	if !qb.checkColumns(column) {
+		return qb
+	}
+	safeCol, err := qb.dialect.EscapeIdentifier(column)
+	if err != nil {
+		qb.err = err
+		return qb
+	}
+
+	direction = ValidateDirection(direction)
+	if lastValue != nil {
+		operator := ">"
+		if direction == "DESC" {
+			operator = "<"
+		}
+		qb.conditions = append(qb.conditions, sqlClause{
+			sql:  safeCol + " " + operator + " ?",
+			args: []interface{}{lastValue},
+		})
+	}
+	qb.orderBy = safeCol + " " + direction
+	return qb
+}
+
+// EncodeCursor encodes a cursor value as an opaque URL-safe string.
+func EncodeCursor(value interface{}) (string, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("encode cursor error: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// DecodeCursor decodes a cursor created by EncodeCursor into dest, which must be a pointer.
+func DecodeCursor(cursor string, dest interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return fmt.Errorf("decode cursor error: %w", err)
+	}
+	if err := json.Unmarshal(data, dest); err != nil {
+		return fmt.Errorf("decode cursor error: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package gdct
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAfterCursor(t *testing.T) {
+	tests := []struct {
+		name          string
+		qb            *QueryBuilder
+		expectedQuery string
+		expectedArgs  int
+	}{
+		{
+			name:          "Ascending",
+			qb:            BuildSelect(PostgreSQL, "posts").Where("user_id = ?", 3).AfterCursor("id", 120, "asc").Limit(20),
+			expectedQuery: "SELECT * FROM posts WHERE user_id = $1 AND id > $2 ORDER BY id ASC LIMIT $3",
+			expectedArgs:  3,
+		},
+		{
+			name:          "Descending",
+			qb:            BuildSelect(Mysql, "posts").AfterCursor("id", 120, "DESC").Limit(20),
+			expectedQuery: "SELECT * FROM posts WHERE id < ? ORDER BY id DESC LIMIT ?",
+			expectedArgs:  2,
+		},
+		{
+			name:          "First page",
+			qb:            BuildSelect(Sqlite, "posts").AfterCursor("id", nil, "DESC").Limit(20),
+			expectedQuery: "SELECT * FROM posts ORDER BY id DESC LIMIT ?",
+			expectedArgs:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args, err := tt.qb.Build()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if query != tt.expectedQuery {
+				t.Errorf("Expected %q, got %q", tt.expectedQuery, query)
+			}
+			if len(args) != tt.expectedArgs {
+				t.Errorf("Expected %d args, got %d", tt.expectedArgs, len(args))
+			}
+		})
+	}
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	cursor, err := EncodeCursor(int64(120))
+	if err != nil {
+		t.Fatalf("EncodeCursor failed: %v", err)
+	}
+	var id int64
+	if err := DecodeCursor(cursor, &id); err != nil {
+		t.Fatalf("DecodeCursor failed: %v", err)
+	}
+	if id != 120 {
+		t.Errorf("Expected 120, got %d", id)
+	}
+
+	createdAt := time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+	cursor, err = EncodeCursor(createdAt)
+	if err != nil {
+		t.Fatalf("EncodeCursor failed: %v", err)
+	}
+	var decoded time.Time
+	if err := DecodeCursor(cursor, &decoded); err != nil {
+		t.Fatalf("DecodeCursor failed: %v", err)
+	}
+	if !decoded.Equal(createdAt) {
+		t.Errorf("Expected %v, got %v", createdAt, decoded)
+	}
+
+	if err := DecodeCursor("not a cursor!", &id); err == nil {
+		t.Errorf("Expected error for invalid cursor")
+	}
+}
@@ -0,0 +1,99 @@
+package gdct
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+)
+
+// createTableRegexp matches the leading CREATE [TEMP|TEMPORARY] TABLE keywords of a statement
+// together with an IF NOT EXISTS that may already follow them.
+var createTableRegexp = regexp.MustCompile(`(?i)^(\s*CREATE\s+(?:TEMP\s+|TEMPORARY\s+)?TABLE\s+)(IF\s+NOT\s+EXISTS\s+)?`)
+
+// TruncateTable removes every row of the table.
+// SQLite has no TRUNCATE statement, so DELETE FROM is used instead.
+func (connect *DataBaseConnector) TruncateTable(table string) error {
+	safeTable, err := connect.escapeTable(table)
+	if err != nil {
+		return err
+	}
+
+	query := "TRUNCATE TABLE " + safeTable
+	if connect.dbType == Sqlite {
+		query = "DELETE FROM " + safeTable
+	}
+
+	if _, err := connect.Exec(query); err != nil {
+		return fmt.Errorf("truncate table %s error: %w", table, err)
+	}
+	return nil
+}
+
+// DropTable drops the table, adding IF EXISTS when ifExists is true.
+func (connect *DataBaseConnector) DropTable(table string, ifExists bool) error {
+	safeTable, err := connect.escapeTable(table)
+	if err != nil {
+		return err
+	}
+
+	query := "DROP TABLE " + safeTable
+	if ifExists {
+		query = "DROP TABLE IF EXISTS " + safeTable
+	}
+
+	if _, err := connect.Exec(query); err != nil {
+		return fmt.Errorf("drop table %s error: %w", table, err)
+	}
+	return nil
+}
+
+// escapeTable validates and escapes a table name for the connector dialect.
+func (connect *DataBaseConnector) escapeTable(table string) (string, error) {
+	dialect, ok := lookupDialect(connect.dbType)
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrInvalidDBType, connect.dbType)
+	}
+	safeTable, err := dialect.EscapeIdentifier(table)
+	if err != nil {
+		return "", fmt.Errorf("invalid table name: %w", err)
+	}
+	return safeTable, nil
+}
+
+// CreateTableContext executes the DDL statements in a single transaction, rolling back every statement if one fails.
+// Cancelling ctx aborts the running statement and rolls the batch back.
+func (connect *DataBaseConnector) CreateTableContext(ctx context.Context, queryList []string) error {
+	return connect.WithTransaction(ctx, nil, func(tx *sql.Tx) error {
+		return execStatements(ctx, tx, queryList)
+	})
+}
+
+// execStatements executes the statements in order, naming the failing one in the returned error.
+func execStatements(ctx context.Context, tx *sql.Tx, queryList []string) error {
+	for i, queryString := range queryList {
+		if _, execErr := tx.ExecContext(ctx, queryString); execErr != nil {
+			return fmt.Errorf("exec statement %d (%s) error: %w", i, querySnippet(queryString), execErr)
+		}
+	}
+	return nil
+}
+
+// CreateTableIfNotExists executes the DDL statements like CreateTableContext,
+// adding IF NOT EXISTS to every CREATE TABLE so that running it again does not fail on existing tables.
+func (connect *DataBaseConnector) CreateTableIfNotExists(queryList []string) error {
+	rewritten := make([]string, len(queryList))
+	for i, queryString := range queryList {
+		rewritten[i] = ifNotExists(queryString)
+	}
+	return connect.CreateTableContext(context.Background(), rewritten)
+}
+
+// ifNotExists adds IF NOT EXISTS to a CREATE TABLE statement, leaving other statements untouched.
+func ifNotExists(queryString string) string {
+	loc := createTableRegexp.FindStringSubmatchIndex(queryString)
+	if loc == nil || loc[4] >= 0 {
+		return queryString
+	}
+	return queryString[:loc[3]] + "IF NOT EXISTS " + queryString[loc[3]:]
+}
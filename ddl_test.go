@@ -0,0 +1,125 @@
+package gdct
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTruncateTableSqlite(t *testing.T) {
+	conn := openTestSqlite(t, DBConfig{})
+	createTestUsers(t, conn)
+
+	if _, err := InsertBatched(conn, "users", []map[string]interface{}{{"name": "John"}, {"name": "Jane"}}, 0); err != nil {
+		t.Fatalf("InsertBatched failed: %v", err)
+	}
+
+	if err := conn.TruncateTable("users"); err != nil {
+		t.Fatalf("TruncateTable failed: %v", err)
+	}
+
+	count, err := Count(conn, BuildSelect(Sqlite, "users"))
+	if err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("Expected 0 rows after truncate, got %d", count)
+	}
+}
+
+func TestDropTableSqlite(t *testing.T) {
+	conn := openTestSqlite(t, DBConfig{})
+	createTestUsers(t, conn)
+
+	if err := conn.DropTable("users", false); err != nil {
+		t.Fatalf("DropTable failed: %v", err)
+	}
+	if err := conn.DropTable("users", false); err == nil {
+		t.Errorf("Expected error dropping a missing table without IF EXISTS")
+	}
+	if err := conn.DropTable("users", true); err != nil {
+		t.Errorf("Expected no error with IF EXISTS, got %v", err)
+	}
+
+	if err := conn.DropTable("users; DROP TABLE x", true); !errors.Is(err, ErrInvalidIdentifier) {
+		t.Errorf("Expected ErrInvalidIdentifier, got %v", err)
+	}
+}
+
+func TestCreateTableContextCancel(t *testing.T) {
+	conn := openTestSqlite(t, DBConfig{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	// The recursive query never finishes on its own, so the batch is cancelled after the first statement
+	err := conn.CreateTableContext(ctx, []string{
+		"CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT)",
+		"WITH RECURSIVE n(x) AS (SELECT 1 UNION ALL SELECT x + 1 FROM n) SELECT COUNT(*) FROM n",
+		"CREATE TABLE posts (id INTEGER PRIMARY KEY)",
+	})
+	if err == nil {
+		t.Fatalf("Expected error from cancelled context")
+	}
+
+	var count int
+	if err := conn.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name IN ('users', 'posts')").Scan(&count); err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("Expected cancelled batch to be rolled back, found %d tables", count)
+	}
+}
+
+func TestCreateTableStatementError(t *testing.T) {
+	conn := openTestSqlite(t, DBConfig{})
+
+	err := conn.SqCreateTable([]string{
+		"CREATE TABLE users (id INTEGER PRIMARY KEY)",
+		"CREATE TABLE posts (id INTEGER PRIMARY KEY,, title TEXT, body TEXT, created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP)",
+	})
+	if err == nil {
+		t.Fatalf("Expected error for malformed statement")
+	}
+	if !strings.Contains(err.Error(), "statement 1 (CREATE TABLE posts") {
+		t.Errorf("Expected error to name statement 1, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "...)") {
+		t.Errorf("Expected long statement to be truncated, got %v", err)
+	}
+}
+
+func TestIfNotExists(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"CREATE TABLE users (id INTEGER)", "CREATE TABLE IF NOT EXISTS users (id INTEGER)"},
+		{"  create temporary table tmp (id INTEGER)", "  create temporary table IF NOT EXISTS tmp (id INTEGER)"},
+		{"CREATE TABLE IF NOT EXISTS users (id INTEGER)", "CREATE TABLE IF NOT EXISTS users (id INTEGER)"},
+		{"CREATE INDEX idx_users_name ON users (name)", "CREATE INDEX idx_users_name ON users (name)"},
+		{"INSERT INTO logs (msg) VALUES ('CREATE TABLE x')", "INSERT INTO logs (msg) VALUES ('CREATE TABLE x')"},
+	}
+
+	for _, tt := range tests {
+		if got := ifNotExists(tt.input); got != tt.expected {
+			t.Errorf("Expected %q, got %q", tt.expected, got)
+		}
+	}
+}
+
+func TestCreateTableIfNotExistsTwice(t *testing.T) {
+	conn := openTestSqlite(t, DBConfig{})
+	queryList := []string{
+		"CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT)",
+		"CREATE TABLE posts (id INTEGER PRIMARY KEY, user_id INTEGER)",
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := conn.CreateTableIfNotExists(queryList); err != nil {
+			t.Fatalf("Run %d failed: %v", i+1, err)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+package gdct
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+)
+
+// Dialect describes the SQL syntax differences of a database.
+// Implement it and call RegisterDialect to build queries for databases gdct does not support out of the box.
+type Dialect interface {
+	// Placeholder returns the bind parameter for the given 1-based argument index.
+	Placeholder(index int) string
+	// EscapeIdentifier validates and escapes a table or column name.
+	EscapeIdentifier(name string) (string, error)
+	// LimitOffset returns the paging clause for the given placeholders.
+	// Values are bound in the order their placeholders appear in the clause.
+	// An empty placeholder means the limit or offset is not set.
+	LimitOffset(limit, offset string) string
+}
+
+var (
+	dialectsMu sync.RWMutex
+	dialects   = map[DBType]Dialect{
+		PostgreSQL: postgresDialect{},
+		MariaDB:    mysqlDialect{},
+		Mysql:      mysqlDialect{},
+		Sqlite:     sqliteDialect{},
+		SQLServer:  sqlserverDialect{},
+	}
+)
+
+/*
+RegisterDialect
+
+@ dbType: Database type the dialect is used for
+@ dialect: Dialect implementation
+@ Return: Error if any
+
+Registering an existing database type replaces its dialect.
+*/
+func RegisterDialect(dbType DBType, dialect Dialect) error {
+	if dbType == "" {
+		return fmt.Errorf("%w: empty database type", ErrInvalidDBType)
+	}
+	if dialect == nil {
+		return fmt.Errorf("dialect cannot be nil")
+	}
+
+	dialectsMu.Lock()
+	defer dialectsMu.Unlock()
+	dialects[dbType] = dialect
+	return nil
+}
+
+// lookupDialect returns the dialect registered for dbType.
+func lookupDialect(dbType DBType) (Dialect, bool) {
+	dialectsMu.RLock()
+	defer dialectsMu.RUnlock()
+	dialect, ok := dialects[dbType]
+	return dialect, ok
+}
+
+// dialectFor returns the dialect registered for dbType, defaulting to "?" placeholders.
+func dialectFor(dbType DBType) Dialect {
+	if dialect, ok := lookupDialect(dbType); ok {
+		return dialect
+	}
+	return baseDialect{}
+}
+
+// baseDialect uses "?" placeholders, unquoted identifiers and LIMIT/OFFSET paging.
+type baseDialect struct{}
+
+func (baseDialect) Placeholder(index int) string {
+	return "?"
+}
+
+func (baseDialect) EscapeIdentifier(name string) (string, error) {
+	if err := ValidateIdentifier(name); err != nil {
+		return "", err
+	}
+
+	// 따옴표 없이 그대로 반환
+	return name, nil
+}
+
+func (baseDialect) LimitOffset(limit, offset string) string {
+	switch {
+	case limit != "" && offset != "":
+		return "LIMIT " + limit + " OFFSET " + offset
+	case limit != "":
+		return "LIMIT " + limit
+	case offset != "":
+		return "OFFSET " + offset
+	default:
+		return ""
+	}
+}
+
+// postgresDialect uses numbered $n placeholders.
+type postgresDialect struct {
+	baseDialect
+}
+
+func (postgresDialect) Placeholder(index int) string {
+	return "$" + strconv.Itoa(index)
+}
+
+// EscapeIdentifier double-quotes PostgreSQL reserved words, leaving other identifiers bare.
+func (postgresDialect) EscapeIdentifier(name string) (string, error) {
+	if err := ValidateIdentifier(name); err != nil {
+		return "", err
+	}
+	return quoteReserved(name, postgresReserved, `"`, true), nil
+}
+
+// mysqlDialect requires a LIMIT whenever OFFSET is used.
+type mysqlDialect struct {
+	baseDialect
+}
+
+// EscapeIdentifier backtick-quotes MariaDB/MySQL reserved words, leaving other identifiers bare.
+func (mysqlDialect) EscapeIdentifier(name string) (string, error) {
+	if err := ValidateIdentifier(name); err != nil {
+		return "", err
+	}
+	return quoteReserved(name, mysqlReserved, "`", false), nil
+}
+
+func (d mysqlDialect) LimitOffset(limit, offset string) string {
+	if limit == "" && offset != "" {
+		limit = "18446744073709551615"
+	}
+	return d.baseDialect.LimitOffset(limit, offset)
+}
+
+// sqliteDialect requires a LIMIT whenever OFFSET is used.
+type sqliteDialect struct {
+	baseDialect
+}
+
+// EscapeIdentifier double-quotes SQLite reserved words, leaving other identifiers bare.
+func (sqliteDialect) EscapeIdentifier(name string) (string, error) {
+	if err := ValidateIdentifier(name); err != nil {
+		return "", err
+	}
+	return quoteReserved(name, sqliteReserved, `"`, false), nil
+}
+
+func (d sqliteDialect) LimitOffset(limit, offset string) string {
+	if limit == "" && offset != "" {
+		limit = "-1"
+	}
+	return d.baseDialect.LimitOffset(limit, offset)
+}
+
+// sqlserverDialect uses @pN placeholders and OFFSET ... FETCH paging, which requires an ORDER BY.
+type sqlserverDialect struct {
+	baseDialect
+}
+
+func (sqlserverDialect) Placeholder(index int) string {
+	return PlaceholderAtP.Placeholder(index)
+}
+
+// EscapeIdentifier double-quotes SQLServer reserved words, leaving other identifiers bare.
+func (sqlserverDialect) EscapeIdentifier(name string) (string, error) {
+	if err := ValidateIdentifier(name); err != nil {
+		return "", err
+	}
+	return quoteReserved(name, sqlserverReserved, `"`, false), nil
+}
+
+func (sqlserverDialect) LimitOffset(limit, offset string) string {
+	if limit == "" && offset == "" {
+		return ""
+	}
+	if offset == "" {
+		offset = "0"
+	}
+	paging := "OFFSET " + offset + " ROWS"
+	if limit != "" {
+		paging += " FETCH NEXT " + limit + " ROWS ONLY"
+	}
+	return paging
+}
+
+// PlaceholderStyle selects how bind parameters are written, overriding the dialect default.
+type PlaceholderStyle int
+
+const (
+	PlaceholderQuestion PlaceholderStyle = iota + 1 // ?
+	PlaceholderDollar                               // $1
+	PlaceholderAtP                                  // @p1
+	PlaceholderColon                                // :1
+)
+
+// IsValid checks if the PlaceholderStyle is one of the supported styles.
+func (s PlaceholderStyle) IsValid() bool {
+	return s >= PlaceholderQuestion && s <= PlaceholderColon
+}
+
+// Placeholder returns the bind parameter for the given 1-based argument index.
+func (s PlaceholderStyle) Placeholder(index int) string {
+	switch s {
+	case PlaceholderDollar:
+		return "$" + strconv.Itoa(index)
+	case PlaceholderAtP:
+		return "@p" + strconv.Itoa(index)
+	case PlaceholderColon:
+		return ":" + strconv.Itoa(index)
+	default:
+		return "?"
+	}
+}
+
+// styledDialect overrides the placeholder style of another dialect.
+type styledDialect struct {
+	Dialect
+	style PlaceholderStyle
+}
+
+func (d styledDialect) Placeholder(index int) string {
+	return d.style.Placeholder(index)
+}
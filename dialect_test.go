@@ -0,0 +1,327 @@
+package gdct
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+)
+
+// colonDialect numbers placeholders as :1, :2, ... and pages with FETCH FIRST.
+type colonDialect struct {
+	baseDialect
+}
+
+func (colonDialect) Placeholder(index int) string {
+	return ":" + strconv.Itoa(index)
+}
+
+func (colonDialect) LimitOffset(limit, offset string) string {
+	clause := ""
+	if offset != "" {
+		clause = "OFFSET " + offset + " ROWS"
+	}
+	if limit != "" {
+		if clause != "" {
+			clause += " "
+		}
+		clause += "FETCH FIRST " + limit + " ROWS ONLY"
+	}
+	return clause
+}
+
+func TestRegisterDialect(t *testing.T) {
+	oracle := DBType("oracle")
+	if oracle.IsValid() {
+		t.Fatalf("DBType %s should be invalid before registration", oracle)
+	}
+
+	if err := RegisterDialect(oracle, colonDialect{}); err != nil {
+		t.Fatalf("RegisterDialect failed: %v", err)
+	}
+	defer func() {
+		dialectsMu.Lock()
+		delete(dialects, oracle)
+		dialectsMu.Unlock()
+	}()
+
+	if !oracle.IsValid() {
+		t.Errorf("DBType %s should be valid after registration", oracle)
+	}
+
+	query, args, err := BuildSelect(oracle, "users", "id", "name").
+		Where("age > ?", 18).
+		WhereIn("status", []interface{}{"active", "pending"}).
+		Limit(10).
+		Offset(20).
+		Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "SELECT id, name FROM users WHERE age > :1 AND status IN (:2, :3) OFFSET :4 ROWS FETCH FIRST :5 ROWS ONLY"
+	if query != expected {
+		t.Errorf("Expected %q, got %q", expected, query)
+	}
+	if fmt.Sprint(args) != "[18 active pending 20 10]" {
+		t.Errorf("Expected args bound in SQL order, got %v", args)
+	}
+
+	if err := RegisterDialect("", colonDialect{}); err == nil {
+		t.Errorf("Expected error registering an empty database type")
+	}
+}
+
+func TestOffsetWithoutLimit(t *testing.T) {
+	tests := []struct {
+		dbType   DBType
+		expected string
+	}{
+		{PostgreSQL, "SELECT * FROM users OFFSET $1"},
+		{Mysql, "SELECT * FROM users LIMIT 18446744073709551615 OFFSET ?"},
+		{Sqlite, "SELECT * FROM users LIMIT -1 OFFSET ?"},
+	}
+
+	for _, tt := range tests {
+		query, _, err := BuildSelect(tt.dbType, "users").Offset(10).Build()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if query != tt.expected {
+			t.Errorf("Expected %q, got %q", tt.expected, query)
+		}
+	}
+}
+
+func TestPlaceholderStyles(t *testing.T) {
+	tests := []struct {
+		name          string
+		style         PlaceholderStyle
+		expectedQuery string
+		expectedList  string
+	}{
+		{"Question", PlaceholderQuestion, "SELECT id FROM users WHERE age > ? AND status IN (?, ?) LIMIT ?", "?, ?"},
+		{"Dollar", PlaceholderDollar, "SELECT id FROM users WHERE age > $1 AND status IN ($2, $3) LIMIT $4", "$2, $3"},
+		{"AtP", PlaceholderAtP, "SELECT id FROM users WHERE age > @p1 AND status IN (@p2, @p3) LIMIT @p4", "@p2, @p3"},
+		{"Colon", PlaceholderColon, "SELECT id FROM users WHERE age > :1 AND status IN (:2, :3) LIMIT :4", ":2, :3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The style is applied after the conditions to make sure it is used at build time.
+			qb := BuildSelect(Mysql, "users", "id").
+				Where("age > ?", 18).
+				WhereIn("status", []interface{}{"active", "pending"}).
+				Limit(10).
+				WithPlaceholderStyle(tt.style)
+
+			query, args, err := qb.Build()
+			if err != nil {
+				t.Fatalf("Build failed: %v", err)
+			}
+			if query != tt.expectedQuery {
+				t.Errorf("Expected %q, got %q", tt.expectedQuery, query)
+			}
+			if len(args) != 4 {
+				t.Errorf("Expected 4 args, got %d", len(args))
+			}
+
+			if got := qb.GeneratePlaceholders(2, 2); got != tt.expectedList {
+				t.Errorf("Expected %q, got %q", tt.expectedList, got)
+			}
+			if got := qb.ReplacePlaceholders("a = ? OR b = ?", 2); got != "a = "+tt.style.Placeholder(2)+" OR b = "+tt.style.Placeholder(3) {
+				t.Errorf("Unexpected ReplacePlaceholders output %q", got)
+			}
+		})
+	}
+}
+
+func TestInvalidPlaceholderStyle(t *testing.T) {
+	_, _, err := BuildSelect(PostgreSQL, "users").WithPlaceholderStyle(PlaceholderStyle(0)).Build()
+	if err == nil {
+		t.Error("Expected error for invalid placeholder style")
+	}
+}
+
+func TestSubqueryPlaceholders(t *testing.T) {
+	sub := BuildCountSelect(PostgreSQL, "orders", "*").Where("orders.status = ?", "paid")
+	qb := BuildSelect(PostgreSQL, "users", "id")
+	qb.SelectRaw(qb.Subquery(sub, "paid_orders")).Where("age > ?", 18)
+
+	query, args, err := qb.Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	expected := "SELECT id, (SELECT COUNT(*) FROM orders WHERE orders.status = $1) AS paid_orders FROM users WHERE age > $2"
+	if query != expected {
+		t.Errorf("Expected %q, got %q", expected, query)
+	}
+	if len(args) != 2 || args[0] != "paid" || args[1] != 18 {
+		t.Errorf("Unexpected args: %v", args)
+	}
+}
+
+func TestReservedWordQuoting(t *testing.T) {
+	// "user" is only reserved by PostgreSQL
+	tests := []struct {
+		dbType        DBType
+		expectedQuery string
+	}{
+		{PostgreSQL, `SELECT "user", o.total FROM "order" o WHERE "user" IN ($1) ORDER BY o."group" ASC`},
+		{Sqlite, `SELECT user, o.total FROM "order" o WHERE user IN (?) ORDER BY o."group" ASC`},
+		{Mysql, "SELECT user, o.total FROM `order` o WHERE user IN (?) ORDER BY o.`group` ASC"},
+		{MariaDB, "SELECT user, o.total FROM `order` o WHERE user IN (?) ORDER BY o.`group` ASC"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dbType.String(), func(t *testing.T) {
+			query, _, err := BuildSelect(tt.dbType, "order o", "user", "o.total").
+				WhereIn("user", []interface{}{"john"}).
+				OrderBy("o.group", "ASC", nil).
+				Build()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if query != tt.expectedQuery {
+				t.Errorf("Expected %q, got %q", tt.expectedQuery, query)
+			}
+		})
+	}
+}
+
+func TestReservedWordQuotingSqlite(t *testing.T) {
+	conn := openTestSqlite(t, DBConfig{})
+	if err := conn.SqCreateTable([]string{`CREATE TABLE "order" (id INTEGER PRIMARY KEY, "user" TEXT)`}); err != nil {
+		t.Fatalf("Create table failed: %v", err)
+	}
+
+	if _, err := conn.ExecBuilder(BuildInsert(Sqlite, "order").Values(map[string]interface{}{"user": "john"})); err != nil {
+		t.Fatalf("Insert failed: %v", err)
+	}
+
+	var user string
+	query, args, err := BuildSelect(Sqlite, "order", "user").Where("id = ?", 1).Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := conn.QueryRow(query, args...).Scan(&user); err != nil {
+		t.Fatalf("Select failed: %v", err)
+	}
+	if user != "john" {
+		t.Errorf("Expected john, got %q", user)
+	}
+}
+
+func TestSQLServerPaging(t *testing.T) {
+	tests := []struct {
+		name          string
+		qb            *QueryBuilder
+		expectedQuery string
+		expectedArgs  []interface{}
+	}{
+		{
+			name:          "offset fetch",
+			qb:            BuildSelect(SQLServer, "users", "id").Where("active = ?", true).OrderBy("id", "ASC", map[string]bool{"id": true}).Limit(10).Offset(20),
+			expectedQuery: "SELECT id FROM users WHERE active = @p1 ORDER BY id ASC OFFSET @p2 ROWS FETCH NEXT @p3 ROWS ONLY",
+			expectedArgs:  []interface{}{true, int64(20), int64(10)},
+		},
+		{
+			name:          "offset fetch with question placeholders",
+			qb:            BuildSelect(SQLServer, "users", "id").Where("active = ?", true).OrderBy("id", "ASC", map[string]bool{"id": true}).Limit(10).Offset(20).WithPlaceholderStyle(PlaceholderQuestion),
+			expectedQuery: "SELECT id FROM users WHERE active = ? ORDER BY id ASC OFFSET ? ROWS FETCH NEXT ? ROWS ONLY",
+			expectedArgs:  []interface{}{true, int64(20), int64(10)},
+		},
+		{
+			name:          "limit only",
+			qb:            BuildSelect(SQLServer, "users", "id").OrderBy("id", "DESC", map[string]bool{"id": true}).Limit(5),
+			expectedQuery: "SELECT id FROM users ORDER BY id DESC OFFSET 0 ROWS FETCH NEXT @p1 ROWS ONLY",
+			expectedArgs:  []interface{}{int64(5)},
+		},
+		{
+			name:          "offset only",
+			qb:            BuildSelect(SQLServer, "users", "id").OrderBy("id", "ASC", map[string]bool{"id": true}).Offset(5),
+			expectedQuery: "SELECT id FROM users ORDER BY id ASC OFFSET @p1 ROWS",
+			expectedArgs:  []interface{}{int64(5)},
+		},
+		{
+			name:          "top",
+			qb:            BuildSelect(SQLServer, "users", "id", "name").Top(3).Where("age > ?", 20),
+			expectedQuery: "SELECT TOP 3 id, name FROM users WHERE age > @p1",
+			expectedArgs:  []interface{}{20},
+		},
+		{
+			name:          "distinct top",
+			qb:            BuildSelect(SQLServer, "users", "name").Distinct().Top(3),
+			expectedQuery: "SELECT DISTINCT TOP 3 name FROM users",
+		},
+		{
+			name:          "reserved words",
+			qb:            BuildSelect(SQLServer, "order", "user", "key").Where("id = ?", 1),
+			expectedQuery: `SELECT "user", "key" FROM "order" WHERE id = @p1`,
+			expectedArgs:  []interface{}{1},
+		},
+		{
+			name:          "random order",
+			qb:            BuildSelect(SQLServer, "users", "id").OrderByRandom().Limit(3),
+			expectedQuery: "SELECT id FROM users ORDER BY NEWID() OFFSET 0 ROWS FETCH NEXT @p1 ROWS ONLY",
+			expectedArgs:  []interface{}{int64(3)},
+		},
+		{
+			name:          "group concat",
+			qb:            BuildSelect(SQLServer, "users", "age").SelectGroupConcat("name", ", ", "names").GroupBy("age"),
+			expectedQuery: "SELECT age, STRING_AGG(name, @p1) AS names FROM users GROUP BY age",
+			expectedArgs:  []interface{}{", "},
+		},
+		{
+			name:          "nulls last",
+			qb:            BuildSelect(SQLServer, "users", "id").OrderByMulti(OrderSpec{Column: "age", Direction: "ASC", Nulls: NullsLast}),
+			expectedQuery: "SELECT id FROM users ORDER BY CASE WHEN age IS NULL THEN 1 ELSE 0 END, age ASC",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args, err := tt.qb.Build()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if query != tt.expectedQuery {
+				t.Errorf("Expected %q, got %q", tt.expectedQuery, query)
+			}
+			if fmt.Sprint(args) != fmt.Sprint(tt.expectedArgs) {
+				t.Errorf("Expected args %v, got %v", tt.expectedArgs, args)
+			}
+		})
+	}
+}
+
+func TestSQLServerPagingErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		qb   *QueryBuilder
+	}{
+		{"offset without order", BuildSelect(SQLServer, "users").Offset(10)},
+		{"limit without order", BuildSelect(SQLServer, "users").Limit(10)},
+		{"top with limit", BuildSelect(SQLServer, "users").OrderBy("id", "ASC", map[string]bool{"id": true}).Top(5).Limit(10)},
+		{"top on postgres", BuildSelect(PostgreSQL, "users").Top(5)},
+		{"zero top", BuildSelect(SQLServer, "users").Top(0)},
+		{"explain", BuildSelect(SQLServer, "users").Explain()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := tt.qb.Build(); err == nil {
+				t.Errorf("Expected error")
+			}
+		})
+	}
+
+	_, _, err := BuildSelect(PostgreSQL, "users").Top(5).Build()
+	if !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Expected ErrUnsupported, got %v", err)
+	}
+	_, _, err = BuildSelect(SQLServer, "users").Explain().Build()
+	if !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Expected ErrUnsupported, got %v", err)
+	}
+}
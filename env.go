@@ -0,0 +1,100 @@
+package gdct
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+/*
+ConfigFromEnv
+
+@ prefix: Environment variable prefix (e.g. "DB" reads DB_HOST, DB_PORT, ...)
+@ Return: DBConfig read from the environment and error if any
+
+Variables:
+  - PREFIX_DB (required): database name or file path for SQLite
+  - PREFIX_HOST, PREFIX_PORT, PREFIX_USER, PREFIX_PASSWORD, PREFIX_SSLMODE
+  - PREFIX_MAX_OPEN_CONNS, PREFIX_MAX_IDLE_CONNS: pool sizes
+  - PREFIX_MAX_LIFETIME: duration ("90s", "5m") or number of seconds
+*/
+func ConfigFromEnv(prefix string) (DBConfig, error) {
+	key := func(name string) string {
+		if prefix == "" {
+			return name
+		}
+		return prefix + "_" + name
+	}
+
+	cfg := DBConfig{
+		Host:     os.Getenv(key("HOST")),
+		UserName: os.Getenv(key("USER")),
+		Password: os.Getenv(key("PASSWORD")),
+		Database: os.Getenv(key("DB")),
+	}
+
+	if cfg.Database == "" {
+		return DBConfig{}, fmt.Errorf("missing required environment variable %s", key("DB"))
+	}
+
+	if sslMode, ok := os.LookupEnv(key("SSLMODE")); ok && sslMode != "" {
+		cfg.SslMode = &sslMode
+	}
+
+	port, err := envInt(key("PORT"))
+	if err != nil {
+		return DBConfig{}, err
+	}
+	if port != nil {
+		cfg.Port = *port
+	}
+
+	if cfg.MaxOpenConns, err = envInt(key("MAX_OPEN_CONNS")); err != nil {
+		return DBConfig{}, err
+	}
+	if cfg.MaxIdleConns, err = envInt(key("MAX_IDLE_CONNS")); err != nil {
+		return DBConfig{}, err
+	}
+	if cfg.MaxLifeTime, err = envDuration(key("MAX_LIFETIME")); err != nil {
+		return DBConfig{}, err
+	}
+
+	return cfg, nil
+}
+
+// envInt parses a non-negative integer environment variable, returning nil when unset.
+func envInt(name string) (*int, error) {
+	raw := os.Getenv(name)
+	if raw == "" {
+		return nil, nil
+	}
+
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid integer in %s: %w", name, err)
+	}
+	if value < 0 {
+		return nil, fmt.Errorf("invalid negative value in %s: %d", name, value)
+	}
+	return &value, nil
+}
+
+// envDuration parses a duration environment variable given as a duration string or seconds.
+func envDuration(name string) (*time.Duration, error) {
+	raw := os.Getenv(name)
+	if raw == "" {
+		return nil, nil
+	}
+
+	if seconds, err := strconv.Atoi(raw); err == nil {
+		value := time.Duration(seconds) * time.Second
+		return &value, nil
+	}
+
+	value, err := time.ParseDuration(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid duration in %s: %w", name, err)
+	}
+	return &value, nil
+}
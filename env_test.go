@@ -0,0 +1,55 @@
+package gdct
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("APP_DB_HOST", "localhost")
+	t.Setenv("APP_DB_PORT", "5432")
+	t.Setenv("APP_DB_USER", "user")
+	t.Setenv("APP_DB_PASSWORD", "password")
+	t.Setenv("APP_DB_DB", "myapp")
+	t.Setenv("APP_DB_SSLMODE", "disable")
+	t.Setenv("APP_DB_MAX_OPEN_CONNS", "20")
+	t.Setenv("APP_DB_MAX_IDLE_CONNS", "5")
+	t.Setenv("APP_DB_MAX_LIFETIME", "5m")
+
+	cfg, err := ConfigFromEnv("APP_DB")
+	if err != nil {
+		t.Fatalf("ConfigFromEnv failed: %v", err)
+	}
+
+	if cfg.Host != "localhost" || cfg.Port != 5432 || cfg.UserName != "user" ||
+		cfg.Password != "password" || cfg.Database != "myapp" {
+		t.Errorf("Unexpected config: %+v", cfg)
+	}
+	if cfg.SslMode == nil || *cfg.SslMode != "disable" {
+		t.Errorf("Expected sslmode disable, got %v", cfg.SslMode)
+	}
+	if cfg.MaxOpenConns == nil || *cfg.MaxOpenConns != 20 {
+		t.Errorf("Expected max open conns 20, got %v", cfg.MaxOpenConns)
+	}
+	if cfg.MaxIdleConns == nil || *cfg.MaxIdleConns != 5 {
+		t.Errorf("Expected max idle conns 5, got %v", cfg.MaxIdleConns)
+	}
+	if cfg.MaxLifeTime == nil || *cfg.MaxLifeTime != 5*time.Minute {
+		t.Errorf("Expected max lifetime 5m, got %v", cfg.MaxLifeTime)
+	}
+}
+
+func TestConfigFromEnvErrors(t *testing.T) {
+	t.Setenv("APP_DB_HOST", "localhost")
+
+	if _, err := ConfigFromEnv("APP_DB"); err == nil {
+		t.Errorf("Expected error for missing APP_DB_DB")
+	}
+
+	t.Setenv("APP_DB_DB", "myapp")
+	t.Setenv("APP_DB_PORT", "not-a-port")
+
+	if _, err := ConfigFromEnv("APP_DB"); err == nil {
+		t.Errorf("Expected error for invalid APP_DB_PORT")
+	}
+}
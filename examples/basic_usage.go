@@ -80,21 +80,18 @@ func dynamicQueryExample() {
 		"created_at": true,
 	}
 
-	// Build query dynamically
-	qb := gdct.BuildSelect(gdct.PostgreSQL, "users", "id", "name", "email", "age")
-
-	// Add conditions only if parameters are provided
-	if searchName != "" {
-		qb = qb.Where("name ILIKE ?", "%"+searchName+"%")
-	}
-	if minAge > 0 {
-		qb = qb.Where("age >= ?", minAge)
-	}
-	if sortBy != "" {
-		qb = qb.OrderBy(sortBy, "ASC", allowedSortColumns)
-	}
-
-	query, args, err := qb.Build()
+	// Build query dynamically, adding conditions only if parameters are provided
+	query, args, err := gdct.BuildSelect(gdct.PostgreSQL, "users", "id", "name", "email", "age").
+		When(searchName != "", func(qb *gdct.QueryBuilder) *gdct.QueryBuilder {
+			return qb.Where("name ILIKE ?", "%"+searchName+"%")
+		}).
+		When(minAge > 0, func(qb *gdct.QueryBuilder) *gdct.QueryBuilder {
+			return qb.Where("age >= ?", minAge)
+		}).
+		Unless(sortBy == "", func(qb *gdct.QueryBuilder) *gdct.QueryBuilder {
+			return qb.OrderBy(sortBy, "ASC", allowedSortColumns)
+		}).
+		Build()
 	if err != nil {
 		log.Printf("Dynamic query build failed: %v", err)
 		return
@@ -158,7 +155,7 @@ func crudOperationsExample() {
 	// UPDATE example
 	updateData := map[string]interface{}{
 		"last_login":  time.Now(),
-		"login_count": "login_count + 1", // Raw SQL expression
+		"login_count": gdct.Raw("login_count + 1"), // Raw SQL expression
 		"updated_at":  time.Now(),
 	}
 
@@ -0,0 +1,486 @@
+package gdct
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrStopIteration is returned from a ForEachRow callback to stop iterating without an error.
+var ErrStopIteration = errors.New("stop iteration")
+
+// Querier is the query execution interface used by the builder execution helpers.
+// It is implemented by *DataBaseConnector, *sql.DB and *sql.Tx, and can be mocked in tests.
+type Querier interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// ExecBuilder builds and executes an INSERT, UPDATE or DELETE query builder.
+func (connect *DataBaseConnector) ExecBuilder(qb *QueryBuilder) (sql.Result, error) {
+	return execBuilder(connect, qb)
+}
+
+// QueryBuilderAll builds and executes a query, mapping every row into dest.
+// dest must be a pointer to a slice of structs (matched by `db` tag) or scalar values.
+func (connect *DataBaseConnector) QueryBuilderAll(qb *QueryBuilder, dest interface{}) error {
+	return queryBuilderAll(connect, qb, dest)
+}
+
+// QueryBuilderGet builds and executes a query, mapping the first row into dest.
+// Returns sql.ErrNoRows when the query matches no rows.
+func (connect *DataBaseConnector) QueryBuilderGet(qb *QueryBuilder, dest interface{}) error {
+	return queryBuilderGet(connect, qb, dest)
+}
+
+func execBuilder(conn Querier, qb *QueryBuilder) (sql.Result, error) {
+	query, args, err := qb.Build()
+	if err != nil {
+		return nil, err
+	}
+	if qb.op == "SELECT" {
+		return nil, fmt.Errorf("ExecBuilder() cannot execute SELECT queries")
+	}
+
+	result, err := conn.Exec(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("exec builder query error: %w", err)
+	}
+	return result, nil
+}
+
+func queryBuilderAll(conn Querier, qb *QueryBuilder, dest interface{}) error {
+	rows, err := queryBuilder(conn, qb)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	return scanAll(rows, dest)
+}
+
+func queryBuilderGet(conn Querier, qb *QueryBuilder, dest interface{}) error {
+	rows, err := queryBuilder(conn, qb)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	return scanOne(rows, dest)
+}
+
+// queryBuilder builds and executes a row-returning query builder.
+// Note: Caller is responsible for closing the returned *sql.Rows.
+func queryBuilder(conn Querier, qb *QueryBuilder) (*sql.Rows, error) {
+	query, args, err := qb.Build()
+	if err != nil {
+		return nil, err
+	}
+	if qb.op != "SELECT" && qb.returning == "" {
+		return nil, fmt.Errorf("%s query without RETURNING clause does not return rows", qb.op)
+	}
+
+	rows, err := conn.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query builder execution error: %w", err)
+	}
+	return rows, nil
+}
+
+/*
+InsertGetId
+
+@ conn: Database connection to execute the INSERT on
+@ idColumn: Primary key column of the inserted row
+@ Return: Primary key of the inserted row and error if any
+
+PostgreSQL and SQLite read the key through a RETURNING clause,
+MariaDB/MySQL and SQLite libraries older than 3.35.0 use LastInsertId.
+The SQLite version is checked once per DataBaseConnector.
+*/
+func (qb *QueryBuilder) InsertGetId(conn Querier, idColumn string) (int64, error) {
+	if qb.err != nil {
+		return 0, qb.err
+	}
+	if qb.op != "INSERT" {
+		return 0, fmt.Errorf("InsertGetId() can only be used with INSERT operation")
+	}
+
+	var id int64
+
+	useReturning := qb.dbType == PostgreSQL
+	if qb.dbType == Sqlite {
+		supported, err := sqliteReturningSupport(conn)
+		if err != nil {
+			return 0, err
+		}
+		useReturning = supported
+	}
+
+	switch {
+	case useReturning:
+		safeCol, err := qb.dialect.EscapeIdentifier(idColumn)
+		if err != nil {
+			return 0, fmt.Errorf("invalid id column: %w", err)
+		}
+		// The RETURNING clause goes on a clone so the caller's builder is left unchanged
+		returningQb := qb.Clone()
+		returningQb.returning = safeCol
+
+		query, args, err := returningQb.Build()
+		if err != nil {
+			return 0, err
+		}
+
+		if err := conn.QueryRow(query, args...).Scan(&id); err != nil {
+			return 0, fmt.Errorf("scan returning id error: %w", err)
+		}
+	default:
+		query, args, err := qb.Build()
+		if err != nil {
+			return 0, err
+		}
+
+		result, err := conn.Exec(query, args...)
+		if err != nil {
+			return 0, fmt.Errorf("exec insert query error: %w", err)
+		}
+
+		id, err = result.LastInsertId()
+		if err != nil {
+			return 0, fmt.Errorf("last insert id error: %w", err)
+		}
+	}
+
+	return id, nil
+}
+
+/*
+InsertReturning
+
+@ conn: Database connection to execute the INSERT on
+@ Return: Values of the RETURNING columns of the inserted row and error if any
+
+The values are scanned into a new slice in RETURNING order; type-assert them as needed.
+Returns sql.ErrNoRows when no row is returned.
+*/
+func (qb *QueryBuilder) InsertReturning(conn Querier) ([]interface{}, error) {
+	if qb.err != nil {
+		return nil, qb.err
+	}
+	if qb.op != "INSERT" {
+		return nil, fmt.Errorf("InsertReturning() can only be used with INSERT operation")
+	}
+	if qb.returning == "" {
+		return nil, fmt.Errorf("InsertReturning() requires a RETURNING clause")
+	}
+
+	result, err := queryReturning(conn, qb)
+	if err != nil {
+		return nil, err
+	}
+	if result.Returned == nil {
+		return nil, sql.ErrNoRows
+	}
+	return result.Returned, nil
+}
+
+/*
+UpdateAffected
+
+@ conn: Database connection to execute the UPDATE on
+@ Return: Number of rows changed and error if any
+*/
+func (qb *QueryBuilder) UpdateAffected(conn Querier) (int64, error) {
+	return qb.execAffected(conn, "UpdateAffected", "UPDATE")
+}
+
+/*
+DeleteAffected
+
+@ conn: Database connection to execute the DELETE on
+@ Return: Number of rows deleted and error if any
+*/
+func (qb *QueryBuilder) DeleteAffected(conn Querier) (int64, error) {
+	return qb.execAffected(conn, "DeleteAffected", "DELETE")
+}
+
+// execAffected executes a builder of the given operation and returns its RowsAffected.
+func (qb *QueryBuilder) execAffected(conn Querier, method, op string) (int64, error) {
+	if qb.err != nil {
+		return 0, qb.err
+	}
+	if qb.op != op {
+		return 0, fmt.Errorf("%s() can only be used with %s operation", method, op)
+	}
+
+	result, err := execBuilder(conn, qb)
+	if err != nil {
+		return 0, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("rows affected error: %w", err)
+	}
+	return affected, nil
+}
+
+// ExecResult is the outcome of a write query normalized across databases.
+type ExecResult struct {
+	LastInsertId int64         // Generated key of an INSERT on MariaDB/MySQL/SQLite without RETURNING
+	RowsAffected int64         // Rows changed, or rows returned when RETURNING is used
+	Returned     []interface{} // Values of the first RETURNING row
+}
+
+/*
+ExecWithResult
+
+@ conn: Database connection to execute the query on
+@ qb: INSERT, UPDATE or DELETE query builder
+@ Return: Normalized result and error if any
+
+Builders with a RETURNING clause are queried so the returned values are kept.
+*/
+func ExecWithResult(conn Querier, qb *QueryBuilder) (*ExecResult, error) {
+	if qb.err != nil {
+		return nil, qb.err
+	}
+	if qb.op == "SELECT" {
+		return nil, fmt.Errorf("ExecWithResult() cannot execute SELECT queries")
+	}
+	if qb.returning != "" {
+		return queryReturning(conn, qb)
+	}
+
+	result, err := execBuilder(conn, qb)
+	if err != nil {
+		return nil, err
+	}
+
+	execResult := &ExecResult{}
+	if execResult.RowsAffected, err = result.RowsAffected(); err != nil {
+		return nil, fmt.Errorf("rows affected error: %w", err)
+	}
+	if qb.op == "INSERT" && qb.dbType != PostgreSQL {
+		if execResult.LastInsertId, err = result.LastInsertId(); err != nil {
+			return nil, fmt.Errorf("last insert id error: %w", err)
+		}
+	}
+	return execResult, nil
+}
+
+func queryReturning(conn Querier, qb *QueryBuilder) (*ExecResult, error) {
+	rows, err := queryBuilder(conn, qb)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("returning columns error: %w", err)
+	}
+
+	execResult := &ExecResult{}
+	for rows.Next() {
+		execResult.RowsAffected++
+		if execResult.Returned != nil {
+			continue
+		}
+		values := make([]interface{}, len(columns))
+		targets := make([]interface{}, len(columns))
+		for i := range values {
+			targets[i] = &values[i]
+		}
+		if err := rows.Scan(targets...); err != nil {
+			return nil, fmt.Errorf("scan returning values error: %w", err)
+		}
+		execResult.Returned = values
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+	return execResult, nil
+}
+
+// SelectAll builds and executes a query builder, mapping every row into T via `db` tags.
+// An empty result returns an empty, non-nil slice.
+func SelectAll[T any](conn Querier, qb *QueryBuilder) ([]T, error) {
+	items := make([]T, 0)
+	if err := queryBuilderAll(conn, qb, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// SelectOne builds and executes a query builder, mapping the first row into T via `db` tags.
+// A LIMIT 1 is applied when the builder has no limit. Returns sql.ErrNoRows when nothing matches.
+func SelectOne[T any](conn Querier, qb *QueryBuilder) (T, error) {
+	var item T
+
+	if qb.limit == 0 {
+		qb = qb.Clone().Limit(1)
+	}
+
+	if err := queryBuilderGet(conn, qb, &item); err != nil {
+		return item, err
+	}
+	return item, nil
+}
+
+// Count executes the count query derived from a SELECT builder and returns the number of matching rows.
+// ORDER BY, LIMIT and OFFSET of the builder are ignored.
+func Count(conn Querier, qb *QueryBuilder) (int64, error) {
+	query, args, err := qb.CountQuery().Build()
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	if err := conn.QueryRow(query, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("scan count error: %w", err)
+	}
+	return count, nil
+}
+
+// Exists reports whether a SELECT builder matches any row using SELECT EXISTS(SELECT 1 ...).
+// ORDER BY, LIMIT and OFFSET of the builder are ignored. DISTINCT, GROUP BY and HAVING queries
+// are checked through the derived table of CountQuery, so HAVING still filters the groups.
+func Exists(conn Querier, qb *QueryBuilder) (bool, error) {
+	existsQb := qb.CountQuery()
+	existsQb.columns = []string{"1"}
+
+	query, args, err := existsQb.Build()
+	if err != nil {
+		return false, err
+	}
+
+	var exists bool
+	if err := conn.QueryRow("SELECT EXISTS("+query+")", args...).Scan(&exists); err != nil {
+		return false, fmt.Errorf("scan exists error: %w", err)
+	}
+	return exists, nil
+}
+
+// Pluck executes a SELECT builder selecting only the given column and scans every value into a slice.
+func Pluck[T any](conn Querier, qb *QueryBuilder, column string) ([]T, error) {
+	if qb.err != nil {
+		return nil, qb.err
+	}
+	if qb.op != "SELECT" {
+		return nil, fmt.Errorf("Pluck() can only be used with SELECT queries")
+	}
+
+	safeCol, err := qb.dialect.EscapeIdentifier(column)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pluck column: %w", err)
+	}
+
+	pluckQb := qb.Clone()
+	pluckQb.columns = []string{safeCol}
+	pluckQb.args = nil
+
+	return SelectAll[T](conn, pluckQb)
+}
+
+/*
+ForEachRow
+
+@ conn: Database connection to execute the query on
+@ qb: Row-returning query builder
+@ fn: Called once per row, typically to Scan it
+@ Return: Error of the query, of fn or of the iteration if any
+
+Rows are closed when iteration ends. Returning ErrStopIteration from fn stops early and yields nil;
+any other error stops early and is returned.
+*/
+func ForEachRow(conn Querier, qb *QueryBuilder, fn func(*sql.Rows) error) error {
+	rows, err := queryBuilder(conn, qb)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err := fn(rows); err != nil {
+			if errors.Is(err, ErrStopIteration) {
+				return nil
+			}
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("rows iteration error: %w", err)
+	}
+	return nil
+}
+
+/*
+EachByChunk
+
+@ conn: Database connection to execute the queries on
+@ qb: SELECT builder; its ORDER BY, LIMIT and OFFSET are replaced
+@ keyColumn: Unique, ordered column the chunks are keyed on; it must be selected
+@ chunkSize: Maximum number of rows per chunk
+@ fn: Called once per non-empty chunk
+@ Return: Error of a query or of fn if any
+
+Each chunk is read with keyColumn > last key ORDER BY keyColumn LIMIT chunkSize,
+so no query scans the rows of earlier chunks as a deep OFFSET would. qb itself is not modified.
+*/
+func EachByChunk(conn Querier, qb *QueryBuilder, keyColumn string, chunkSize int, fn func([]map[string]interface{}) error) error {
+	if qb.err != nil {
+		return qb.err
+	}
+	if qb.op != "SELECT" {
+		return fmt.Errorf("EachByChunk() can only be used with SELECT queries")
+	}
+	if chunkSize <= 0 {
+		return fmt.Errorf("EachByChunk() requires a positive chunk size, got %d", chunkSize)
+	}
+
+	// Result columns carry the bare name of a qualified key column
+	keyName := keyColumn
+	if i := strings.LastIndexByte(keyName, '.'); i >= 0 {
+		keyName = keyName[i+1:]
+	}
+
+	var lastKey interface{}
+	for {
+		chunkQb := qb.Clone()
+		chunkQb.offset = 0
+		chunkQb.AfterCursor(keyColumn, lastKey, "ASC").Limit(chunkSize)
+
+		rows, err := queryBuilder(conn, chunkQb)
+		if err != nil {
+			return err
+		}
+		chunk, err := scanMaps(rows)
+		rows.Close()
+		if err != nil {
+			return err
+		}
+		if len(chunk) == 0 {
+			return nil
+		}
+
+		key, ok := chunk[len(chunk)-1][keyName]
+		if !ok {
+			return fmt.Errorf("EachByChunk() key column %q is not selected", keyColumn)
+		}
+		if err := fn(chunk); err != nil {
+			return err
+		}
+		if len(chunk) < chunkSize {
+			return nil
+		}
+		lastKey = key
+	}
+}
@@ -0,0 +1,859 @@
+package gdct
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type testUser struct {
+	ID   int64  `db:"id"`
+	Name string `db:"name"`
+	Age  int    `db:"age"`
+}
+
+// createTestUsers creates a users table on the given connection.
+func createTestUsers(t *testing.T, conn *DataBaseConnector) {
+	t.Helper()
+
+	err := conn.SqCreateTable([]string{
+		"CREATE TABLE users (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT NOT NULL, age INTEGER NOT NULL DEFAULT 0)",
+	})
+	if err != nil {
+		t.Fatalf("Create table failed: %v", err)
+	}
+}
+
+func TestInsertGetId(t *testing.T) {
+	conn := openTestSqlite(t, DBConfig{})
+	createTestUsers(t, conn)
+
+	for i, name := range []string{"John", "Jane"} {
+		id, err := BuildInsert(Sqlite, "users").
+			Values(map[string]interface{}{"name": name}).
+			InsertGetId(conn, "id")
+		if err != nil {
+			t.Fatalf("InsertGetId failed: %v", err)
+		}
+		if id != int64(i+1) {
+			t.Errorf("Expected id %d, got %d", i+1, id)
+		}
+	}
+
+	reused := BuildInsert(Sqlite, "users").Values(map[string]interface{}{"name": "Kim"})
+	if _, err := reused.InsertGetId(conn, "id"); err != nil {
+		t.Fatalf("InsertGetId failed: %v", err)
+	}
+	if query, _, _ := reused.Build(); strings.Contains(query, "RETURNING") {
+		t.Errorf("Expected caller's builder without RETURNING, got %q", query)
+	}
+
+	query, _, err := BuildInsert(PostgreSQL, "users").
+		Values(map[string]interface{}{"name": "John"}).
+		Returning("id").
+		Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "INSERT INTO users (name) VALUES ($1) RETURNING id"
+	if query != expected {
+		t.Errorf("Expected %q, got %q", expected, query)
+	}
+
+	_, err = BuildSelect(Sqlite, "users").InsertGetId(conn, "id")
+	if err == nil {
+		t.Errorf("Expected error for InsertGetId on SELECT")
+	}
+}
+
+func TestExecuteBuilder(t *testing.T) {
+	conn := openTestSqlite(t, DBConfig{})
+	createTestUsers(t, conn)
+
+	for _, user := range []testUser{{Name: "John", Age: 30}, {Name: "Jane", Age: 25}} {
+		result, err := conn.ExecBuilder(BuildInsert(Sqlite, "users").
+			Values(map[string]interface{}{"name": user.Name, "age": user.Age}))
+		if err != nil {
+			t.Fatalf("ExecBuilder failed: %v", err)
+		}
+		if affected, _ := result.RowsAffected(); affected != 1 {
+			t.Errorf("Expected 1 affected row, got %d", affected)
+		}
+	}
+
+	var users []testUser
+	err := conn.QueryBuilderAll(BuildSelect(Sqlite, "users").OrderBy("age", "ASC", nil), &users)
+	if err != nil {
+		t.Fatalf("QueryBuilderAll failed: %v", err)
+	}
+	if len(users) != 2 || users[0].Name != "Jane" || users[1].Age != 30 {
+		t.Errorf("Unexpected users: %+v", users)
+	}
+
+	var user testUser
+	err = conn.QueryBuilderGet(BuildSelect(Sqlite, "users").Where("name = ?", "John"), &user)
+	if err != nil {
+		t.Fatalf("QueryBuilderGet failed: %v", err)
+	}
+	if user.ID != 1 || user.Age != 30 {
+		t.Errorf("Unexpected user: %+v", user)
+	}
+
+	err = conn.QueryBuilderGet(BuildSelect(Sqlite, "users").Where("name = ?", "Nobody"), &user)
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Expected sql.ErrNoRows, got %v", err)
+	}
+
+	if _, err := conn.ExecBuilder(BuildSelect(Sqlite, "users")); err == nil {
+		t.Errorf("Expected error executing SELECT through ExecBuilder")
+	}
+
+	if _, err := conn.ExecBuilder(BuildInsert(Sqlite, "")); err == nil {
+		t.Errorf("Expected builder error to be surfaced")
+	}
+}
+
+func TestSelectAll(t *testing.T) {
+	conn := openTestSqlite(t, DBConfig{})
+	createTestUsers(t, conn)
+
+	_, err := conn.Exec("INSERT INTO users (name, age) VALUES ('John', 30), ('Jane', 25), ('Kim', 40)")
+	if err != nil {
+		t.Fatalf("Insert failed: %v", err)
+	}
+
+	users, err := SelectAll[testUser](conn, BuildSelect(Sqlite, "users", "id", "name", "age").
+		Where("age > ?", 26).
+		OrderBy("id", "ASC", nil))
+	if err != nil {
+		t.Fatalf("SelectAll failed: %v", err)
+	}
+	if len(users) != 2 || users[0].Name != "John" || users[1].Name != "Kim" {
+		t.Errorf("Unexpected users: %+v", users)
+	}
+
+	users, err = SelectAll[testUser](conn, BuildSelect(Sqlite, "users").Where("age > ?", 100))
+	if err != nil {
+		t.Fatalf("SelectAll failed: %v", err)
+	}
+	if users == nil || len(users) != 0 {
+		t.Errorf("Expected empty non-nil slice, got %#v", users)
+	}
+
+	if _, err := SelectAll[testUser](conn, BuildSelect(Sqlite, "")); err == nil {
+		t.Errorf("Expected builder error to be surfaced")
+	}
+}
+
+func TestSelectOne(t *testing.T) {
+	conn := openTestSqlite(t, DBConfig{})
+	createTestUsers(t, conn)
+
+	_, err := conn.Exec("INSERT INTO users (name, age) VALUES ('John', 30), ('Jane', 25)")
+	if err != nil {
+		t.Fatalf("Insert failed: %v", err)
+	}
+
+	qb := BuildSelect(Sqlite, "users").Where("name = ?", "Jane")
+	user, err := SelectOne[testUser](conn, qb)
+	if err != nil {
+		t.Fatalf("SelectOne failed: %v", err)
+	}
+	if user.Name != "Jane" || user.Age != 25 {
+		t.Errorf("Unexpected user: %+v", user)
+	}
+	if qb.limit != 0 {
+		t.Errorf("SelectOne should not modify the given builder, got limit %d", qb.limit)
+	}
+
+	_, err = SelectOne[testUser](conn, BuildSelect(Sqlite, "users").Where("name = ?", "Nobody"))
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Expected sql.ErrNoRows, got %v", err)
+	}
+
+	user, err = SelectOne[testUser](conn, BuildSelect(Sqlite, "users").OrderBy("age", "DESC", nil))
+	if err != nil {
+		t.Fatalf("SelectOne failed: %v", err)
+	}
+	if user.Name != "John" {
+		t.Errorf("Expected first row John, got %+v", user)
+	}
+}
+
+func TestCount(t *testing.T) {
+	conn := openTestSqlite(t, DBConfig{})
+	createTestUsers(t, conn)
+
+	_, err := conn.Exec("INSERT INTO users (name, age) VALUES ('John', 30), ('Jane', 25), ('Kim', 40), ('Lee', 15)")
+	if err != nil {
+		t.Fatalf("Insert failed: %v", err)
+	}
+
+	qb := BuildSelect(Sqlite, "users", "id", "name").
+		Where("age > ?", 20).
+		OrderBy("age", "DESC", nil).
+		Limit(1).
+		Offset(1)
+
+	count, err := Count(conn, qb)
+	if err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	if count != 3 {
+		t.Errorf("Expected count 3, got %d", count)
+	}
+
+	query, _, err := qb.CountQuery().Build()
+	if err != nil {
+		t.Fatalf("CountQuery failed: %v", err)
+	}
+	expected := "SELECT COUNT(*) FROM users WHERE age > ?"
+	if query != expected {
+		t.Errorf("Expected %q, got %q", expected, query)
+	}
+}
+
+func TestCountDistinctAndGroups(t *testing.T) {
+	conn := openTestSqlite(t, DBConfig{})
+
+	if _, err := conn.Exec("CREATE TABLE u (id INTEGER PRIMARY KEY, city TEXT NOT NULL)"); err != nil {
+		t.Fatalf("Create table failed: %v", err)
+	}
+	if _, err := conn.Exec("INSERT INTO u (city) VALUES ('Seoul'), ('Seoul'), ('Seoul'), ('Busan'), ('Busan')"); err != nil {
+		t.Fatalf("Insert failed: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		qb            *QueryBuilder
+		expectedQuery string
+		expected      int64
+	}{
+		{
+			name:          "distinct",
+			qb:            BuildSelect(Sqlite, "u", "city").Distinct(),
+			expectedQuery: "SELECT COUNT(*) FROM (SELECT DISTINCT city FROM u) AS t",
+			expected:      2,
+		},
+		{
+			name:          "group by",
+			qb:            BuildSelect(Sqlite, "u", "city").GroupBy("city").OrderBy("city", "ASC", map[string]bool{"city": true}).Limit(1),
+			expectedQuery: "SELECT COUNT(*) FROM (SELECT city FROM u GROUP BY city) AS t",
+			expected:      2,
+		},
+		{
+			name:          "having",
+			qb:            BuildSelect(Sqlite, "u", "city").Where("id > ?", 0).GroupBy("city").Having("COUNT(*) > ?", 2),
+			expectedQuery: "SELECT COUNT(*) FROM (SELECT city FROM u WHERE id > ? GROUP BY city HAVING COUNT(*) > ?) AS t",
+			expected:      1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, _, err := tt.qb.CountQuery().Build()
+			if err != nil {
+				t.Fatalf("CountQuery failed: %v", err)
+			}
+			if query != tt.expectedQuery {
+				t.Errorf("Expected %q, got %q", tt.expectedQuery, query)
+			}
+
+			count, err := Count(conn, tt.qb)
+			if err != nil {
+				t.Fatalf("Count failed: %v", err)
+			}
+			if count != tt.expected {
+				t.Errorf("Expected count %d, got %d", tt.expected, count)
+			}
+		})
+	}
+
+	query, args, err := BuildSelect(PostgreSQL, "u", "city").Where("id > ?", 0).GroupBy("city").Having("COUNT(*) > ?", 2).CountQuery().Build()
+	if err != nil {
+		t.Fatalf("CountQuery failed: %v", err)
+	}
+	expected := "SELECT COUNT(*) FROM (SELECT city FROM u WHERE id > $1 GROUP BY city HAVING COUNT(*) > $2) AS t"
+	if query != expected {
+		t.Errorf("Expected %q, got %q", expected, query)
+	}
+	if len(args) != 2 {
+		t.Errorf("Expected 2 args, got %v", args)
+	}
+}
+
+func TestExists(t *testing.T) {
+	conn := openTestSqlite(t, DBConfig{})
+	createTestUsers(t, conn)
+
+	_, err := conn.Exec("INSERT INTO users (name, age) VALUES ('John', 30), ('Jane', 25)")
+	if err != nil {
+		t.Fatalf("Insert failed: %v", err)
+	}
+
+	exists, err := Exists(conn, BuildSelect(Sqlite, "users").Where("name = ?", "Jane").Limit(10))
+	if err != nil {
+		t.Fatalf("Exists failed: %v", err)
+	}
+	if !exists {
+		t.Errorf("Expected matching row to exist")
+	}
+
+	exists, err = Exists(conn, BuildSelect(Sqlite, "users").Where("age > ?", 50))
+	if err != nil {
+		t.Fatalf("Exists failed: %v", err)
+	}
+	if exists {
+		t.Errorf("Expected no matching row")
+	}
+
+	grouped := []struct {
+		name     string
+		qb       *QueryBuilder
+		expected bool
+	}{
+		{"distinct", BuildSelect(Sqlite, "users", "age").Distinct().Where("age < ?", 30), true},
+		{"group by", BuildSelect(Sqlite, "users", "age").GroupBy("age"), true},
+		{"having match", BuildSelect(Sqlite, "users", "age").GroupBy("age").Having("COUNT(*) >= ?", 1), true},
+		{"having no match", BuildSelect(Sqlite, "users", "age").GroupBy("age").Having("COUNT(*) > ?", 1), false},
+	}
+	for _, tt := range grouped {
+		t.Run(tt.name, func(t *testing.T) {
+			query, _, err := tt.qb.CountQuery().Build()
+			if err != nil {
+				t.Fatalf("CountQuery failed: %v", err)
+			}
+			if !strings.Contains(query, "FROM (SELECT ") {
+				t.Errorf("Expected a derived table, got %q", query)
+			}
+
+			exists, err := Exists(conn, tt.qb)
+			if err != nil {
+				t.Fatalf("Exists failed: %v", err)
+			}
+			if exists != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, exists)
+			}
+		})
+	}
+}
+
+func TestPluck(t *testing.T) {
+	conn := openTestSqlite(t, DBConfig{})
+	createTestUsers(t, conn)
+
+	_, err := conn.Exec("INSERT INTO users (name, age) VALUES ('John', 30), ('Jane', 25), ('Kim', 40)")
+	if err != nil {
+		t.Fatalf("Insert failed: %v", err)
+	}
+
+	qb := BuildSelect(Sqlite, "users", "id", "name", "age").Where("age >= ?", 30).OrderBy("id", "ASC", nil)
+
+	ids, err := Pluck[int64](conn, qb, "id")
+	if err != nil {
+		t.Fatalf("Pluck ids failed: %v", err)
+	}
+	if len(ids) != 2 || ids[0] != 1 || ids[1] != 3 {
+		t.Errorf("Expected ids [1 3], got %v", ids)
+	}
+
+	names, err := Pluck[string](conn, qb, "name")
+	if err != nil {
+		t.Fatalf("Pluck names failed: %v", err)
+	}
+	if len(names) != 2 || names[0] != "John" || names[1] != "Kim" {
+		t.Errorf("Expected names [John Kim], got %v", names)
+	}
+}
+
+// recordingQuerier is a Querier mock recording every executed query.
+type recordingQuerier struct {
+	Querier
+	queries []string
+}
+
+func (q *recordingQuerier) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	q.queries = append(q.queries, query)
+	return q.Querier.Query(query, args...)
+}
+
+func (q *recordingQuerier) QueryRow(query string, args ...interface{}) *sql.Row {
+	q.queries = append(q.queries, query)
+	return q.Querier.QueryRow(query, args...)
+}
+
+func (q *recordingQuerier) Exec(query string, args ...interface{}) (sql.Result, error) {
+	q.queries = append(q.queries, query)
+	return q.Querier.Exec(query, args...)
+}
+
+func TestHelpersWithQuerier(t *testing.T) {
+	var _ Querier = (*DataBaseConnector)(nil)
+	var _ Querier = (*sql.DB)(nil)
+	var _ Querier = (*sql.Tx)(nil)
+
+	conn := openTestSqlite(t, DBConfig{})
+	createTestUsers(t, conn)
+
+	mock := &recordingQuerier{Querier: conn.DB}
+
+	if _, err := execBuilder(mock, BuildInsert(Sqlite, "users").Values(map[string]interface{}{"name": "John"})); err != nil {
+		t.Fatalf("execBuilder failed: %v", err)
+	}
+
+	users, err := SelectAll[testUser](mock, BuildSelect(Sqlite, "users"))
+	if err != nil {
+		t.Fatalf("SelectAll failed: %v", err)
+	}
+	if len(users) != 1 || users[0].Name != "John" {
+		t.Errorf("Unexpected users: %+v", users)
+	}
+
+	count, err := Count(mock, BuildSelect(Sqlite, "users"))
+	if err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Expected count 1, got %d", count)
+	}
+
+	expected := []string{
+		"INSERT INTO users (name) VALUES (?)",
+		"SELECT * FROM users",
+		"SELECT COUNT(*) FROM users",
+	}
+	if len(mock.queries) != len(expected) {
+		t.Fatalf("Expected queries %v, got %v", expected, mock.queries)
+	}
+	for i := range expected {
+		if mock.queries[i] != expected[i] {
+			t.Errorf("Expected query %q, got %q", expected[i], mock.queries[i])
+		}
+	}
+}
+
+func TestSqliteReturning(t *testing.T) {
+	conn := openTestSqlite(t, DBConfig{})
+	createTestUsers(t, conn)
+
+	supported, err := conn.SqSupportsReturning()
+	if err != nil {
+		t.Fatalf("SqSupportsReturning failed: %v", err)
+	}
+	if !supported {
+		t.Skip("SQLite library does not support RETURNING")
+	}
+
+	query, args, err := BuildInsert(Sqlite, "users").
+		Values(map[string]interface{}{"name": "John", "age": 30}).
+		Returning("id, name").
+		Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "INSERT INTO users (age, name) VALUES (?, ?) RETURNING id, name"
+	if query != expected {
+		t.Errorf("Expected %q, got %q", expected, query)
+	}
+
+	var id int64
+	var name string
+	if err := conn.QueryRow(query, args...).Scan(&id, &name); err != nil {
+		t.Fatalf("Scan returning failed: %v", err)
+	}
+	if id != 1 || name != "John" {
+		t.Errorf("Expected (1, John), got (%d, %s)", id, name)
+	}
+}
+
+// oldSqliteQuerier reports an SQLite library predating RETURNING support.
+type oldSqliteQuerier struct {
+	recordingQuerier
+}
+
+func (q *oldSqliteQuerier) QueryRow(query string, args ...interface{}) *sql.Row {
+	if query == "SELECT sqlite_version()" {
+		query = "SELECT '3.34.1'"
+	}
+	return q.recordingQuerier.QueryRow(query, args...)
+}
+
+func TestInsertGetIdWithoutReturning(t *testing.T) {
+	conn := openTestSqlite(t, DBConfig{})
+	createTestUsers(t, conn)
+
+	mock := &oldSqliteQuerier{recordingQuerier{Querier: conn}}
+	id, err := BuildInsert(Sqlite, "users").
+		Values(map[string]interface{}{"name": "John"}).
+		InsertGetId(mock, "id")
+	if err != nil {
+		t.Fatalf("InsertGetId failed: %v", err)
+	}
+	if id != 1 {
+		t.Errorf("Expected id 1, got %d", id)
+	}
+
+	expected := "INSERT INTO users (name) VALUES (?)"
+	if last := mock.queries[len(mock.queries)-1]; last != expected {
+		t.Errorf("Expected LastInsertId fallback %q, got %q", expected, last)
+	}
+}
+
+func TestInsertGetIdChecksSqliteVersionOnce(t *testing.T) {
+	var versionChecks int
+	conn := openTestSqlite(t, DBConfig{Logger: func(entry QueryLog) {
+		if entry.Query == "SELECT sqlite_version()" {
+			versionChecks++
+		}
+	}})
+	createTestUsers(t, conn)
+
+	for i := 0; i < 3; i++ {
+		_, err := BuildInsert(Sqlite, "users").
+			Values(map[string]interface{}{"name": "John", "age": 30}).
+			InsertGetId(conn, "id")
+		if err != nil {
+			t.Fatalf("InsertGetId failed: %v", err)
+		}
+	}
+	if versionChecks != 1 {
+		t.Errorf("Expected the SQLite version to be checked once, got %d checks", versionChecks)
+	}
+}
+
+func TestVersionAtLeast(t *testing.T) {
+	tests := []struct {
+		version  string
+		minimum  string
+		expected bool
+	}{
+		{"3.35.0", "3.35.0", true},
+		{"3.49.1", "3.35.0", true},
+		{"3.34.1", "3.35.0", false},
+		{"10.11.6-MariaDB", "10.5", true},
+		{"8.0.36-log", "8.0.40", false},
+	}
+
+	for _, tt := range tests {
+		if got := versionAtLeast(tt.version, tt.minimum); got != tt.expected {
+			t.Errorf("versionAtLeast(%q, %q) = %v, expected %v", tt.version, tt.minimum, got, tt.expected)
+		}
+	}
+}
+
+func TestExecWithResult(t *testing.T) {
+	conn := openTestSqlite(t, DBConfig{})
+	createTestUsers(t, conn)
+
+	// The SQLite database accepts the SQL generated for every dialect used here.
+	tests := []struct {
+		name             string
+		qb               *QueryBuilder
+		expectedInsertId int64
+		expectedAffected int64
+		expectedReturned []interface{}
+	}{
+		{
+			name:             "Mysql insert",
+			qb:               BuildInsert(Mysql, "users").Values(map[string]interface{}{"name": "John"}),
+			expectedInsertId: 1,
+			expectedAffected: 1,
+		},
+		{
+			name:             "Sqlite insert",
+			qb:               BuildInsert(Sqlite, "users").Values(map[string]interface{}{"name": "Jane"}),
+			expectedInsertId: 2,
+			expectedAffected: 1,
+		},
+		{
+			name:             "PostgreSQL insert returning",
+			qb:               BuildInsert(PostgreSQL, "users").Values(map[string]interface{}{"name": "Jake"}).Returning("id, name"),
+			expectedAffected: 1,
+			expectedReturned: []interface{}{int64(3), "Jake"},
+		},
+		{
+			name:             "PostgreSQL update",
+			qb:               BuildUpdate(PostgreSQL, "users").Set(map[string]interface{}{"age": 20}),
+			expectedAffected: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ExecWithResult(conn, tt.qb)
+			if err != nil {
+				t.Fatalf("ExecWithResult failed: %v", err)
+			}
+			if result.LastInsertId != tt.expectedInsertId {
+				t.Errorf("Expected LastInsertId %d, got %d", tt.expectedInsertId, result.LastInsertId)
+			}
+			if result.RowsAffected != tt.expectedAffected {
+				t.Errorf("Expected RowsAffected %d, got %d", tt.expectedAffected, result.RowsAffected)
+			}
+			if len(result.Returned) != len(tt.expectedReturned) {
+				t.Fatalf("Expected Returned %v, got %v", tt.expectedReturned, result.Returned)
+			}
+			for i := range tt.expectedReturned {
+				if result.Returned[i] != tt.expectedReturned[i] {
+					t.Errorf("Expected Returned %v, got %v", tt.expectedReturned, result.Returned)
+				}
+			}
+		})
+	}
+}
+
+func TestInsertReturning(t *testing.T) {
+	conn := openTestSqlite(t, DBConfig{})
+	createTestUsers(t, conn)
+
+	values, err := BuildInsert(Sqlite, "users").
+		Values(map[string]interface{}{"name": "John", "age": 41}).
+		Returning("id", "age").
+		InsertReturning(conn)
+	if err != nil {
+		t.Fatalf("InsertReturning failed: %v", err)
+	}
+	if len(values) != 2 {
+		t.Fatalf("Expected 2 returned values, got %v", values)
+	}
+	if id, ok := values[0].(int64); !ok || id != 1 {
+		t.Errorf("Expected id 1, got %v", values[0])
+	}
+	if age, ok := values[1].(int64); !ok || age != 41 {
+		t.Errorf("Expected age 41, got %v", values[1])
+	}
+
+	_, err = BuildInsert(Sqlite, "users").Values(map[string]interface{}{"name": "Jane"}).InsertReturning(conn)
+	if err == nil {
+		t.Errorf("Expected error for InsertReturning without RETURNING")
+	}
+}
+
+func TestUpdateDeleteAffected(t *testing.T) {
+	conn := openTestSqlite(t, DBConfig{})
+	createTestUsers(t, conn)
+
+	for _, row := range []map[string]interface{}{
+		{"name": "John", "age": 30},
+		{"name": "Jane", "age": 30},
+		{"name": "Kim", "age": 40},
+	} {
+		if _, err := conn.ExecBuilder(BuildInsert(Sqlite, "users").Values(row)); err != nil {
+			t.Fatalf("Insert failed: %v", err)
+		}
+	}
+
+	affected, err := BuildUpdate(Sqlite, "users").
+		Set(map[string]interface{}{"age": 31}).
+		Where("age = ?", 30).
+		UpdateAffected(conn)
+	if err != nil {
+		t.Fatalf("UpdateAffected failed: %v", err)
+	}
+	if affected != 2 {
+		t.Errorf("Expected 2 updated rows, got %d", affected)
+	}
+
+	affected, err = BuildDelete(Sqlite, "users").Where("age = ?", 99).DeleteAffected(conn)
+	if err != nil {
+		t.Fatalf("DeleteAffected failed: %v", err)
+	}
+	if affected != 0 {
+		t.Errorf("Expected 0 deleted rows, got %d", affected)
+	}
+
+	affected, err = BuildDelete(Sqlite, "users").Where("age = ?", 40).DeleteAffected(conn)
+	if err != nil {
+		t.Fatalf("DeleteAffected failed: %v", err)
+	}
+	if affected != 1 {
+		t.Errorf("Expected 1 deleted row, got %d", affected)
+	}
+
+	if _, err := BuildUpdate(Sqlite, "users;").Set(map[string]interface{}{"age": 1}).UpdateAffected(conn); !errors.Is(err, ErrInvalidIdentifier) {
+		t.Errorf("Expected builder error, got %v", err)
+	}
+	if _, err := BuildDelete(Sqlite, "users").Where("id = ?", 1).UpdateAffected(conn); err == nil {
+		t.Errorf("Expected error for UpdateAffected on DELETE")
+	}
+}
+
+func TestForEachRow(t *testing.T) {
+	conn := openTestSqlite(t, DBConfig{})
+	createTestUsers(t, conn)
+
+	names := []string{"Ann", "Bob", "Cid", "Dan"}
+	for _, name := range names {
+		if _, err := conn.ExecBuilder(BuildInsert(Sqlite, "users").Values(map[string]interface{}{"name": name})); err != nil {
+			t.Fatalf("Insert failed: %v", err)
+		}
+	}
+
+	qb := BuildSelect(Sqlite, "users", "name").OrderBy("id", "ASC", map[string]bool{"id": true})
+
+	var visited []string
+	err := ForEachRow(conn, qb, func(rows *sql.Rows) error {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return err
+		}
+		visited = append(visited, name)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ForEachRow failed: %v", err)
+	}
+	if strings.Join(visited, ",") != strings.Join(names, ",") {
+		t.Errorf("Expected %v, got %v", names, visited)
+	}
+
+	visited = nil
+	err = ForEachRow(conn, qb, func(rows *sql.Rows) error {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return err
+		}
+		visited = append(visited, name)
+		if len(visited) == 2 {
+			return ErrStopIteration
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected nil error after ErrStopIteration, got %v", err)
+	}
+	if len(visited) != 2 {
+		t.Errorf("Expected 2 rows before stopping, got %v", visited)
+	}
+
+	failure := errors.New("callback failed")
+	err = ForEachRow(conn, qb, func(rows *sql.Rows) error { return failure })
+	if !errors.Is(err, failure) {
+		t.Errorf("Expected callback error, got %v", err)
+	}
+
+	// Every iteration closed its rows, so no connection stays in use
+	if stats := conn.Stats(); stats.InUse != 0 {
+		t.Errorf("Expected no connections in use, got %d", stats.InUse)
+	}
+}
+
+func TestEachByChunk(t *testing.T) {
+	conn := openTestSqlite(t, DBConfig{})
+	createTestUsers(t, conn)
+
+	for i := 0; i < 5; i++ {
+		row := map[string]interface{}{"name": "user", "age": 20 + i}
+		if _, err := conn.ExecBuilder(BuildInsert(Sqlite, "users").Values(row)); err != nil {
+			t.Fatalf("Insert failed: %v", err)
+		}
+	}
+
+	var chunkSizes []int
+	seen := make(map[int64]int)
+	err := EachByChunk(conn, BuildSelect(Sqlite, "users", "id", "age").Where("age >= ?", 20), "id", 2,
+		func(chunk []map[string]interface{}) error {
+			chunkSizes = append(chunkSizes, len(chunk))
+			for _, row := range chunk {
+				seen[row["id"].(int64)]++
+			}
+			return nil
+		})
+	if err != nil {
+		t.Fatalf("EachByChunk failed: %v", err)
+	}
+
+	if fmt.Sprint(chunkSizes) != "[2 2 1]" {
+		t.Errorf("Expected chunks [2 2 1], got %v", chunkSizes)
+	}
+	if len(seen) != 5 {
+		t.Errorf("Expected 5 distinct rows, got %v", seen)
+	}
+	for id, count := range seen {
+		if count != 1 {
+			t.Errorf("Expected row %d visited once, got %d", id, count)
+		}
+	}
+
+	failure := errors.New("export failed")
+	calls := 0
+	err = EachByChunk(conn, BuildSelect(Sqlite, "users", "id"), "id", 2, func(chunk []map[string]interface{}) error {
+		calls++
+		return failure
+	})
+	if !errors.Is(err, failure) || calls != 1 {
+		t.Errorf("Expected callback error after 1 call, got %v after %d", err, calls)
+	}
+
+	err = EachByChunk(conn, BuildSelect(Sqlite, "users", "name"), "id", 2, func(chunk []map[string]interface{}) error { return nil })
+	if err == nil {
+		t.Errorf("Expected error when the key column is not selected")
+	}
+}
+
+func TestWithTotalCount(t *testing.T) {
+	query, args, err := BuildSelect(PostgreSQL, "users", "id", "name").
+		Where("age > ?", 20).
+		WithTotalCount("total").
+		Limit(2).
+		Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := "SELECT id, name, COUNT(*) OVER() AS total FROM users WHERE age > $1 LIMIT $2"
+	if query != expected {
+		t.Errorf("Expected %q, got %q", expected, query)
+	}
+	if len(args) != 2 {
+		t.Errorf("Expected 2 args, got %v", args)
+	}
+
+	conn := openTestSqlite(t, DBConfig{})
+	createTestUsers(t, conn)
+	for i := 0; i < 5; i++ {
+		if _, err := conn.ExecBuilder(BuildInsert(Sqlite, "users").Values(map[string]interface{}{"name": "user", "age": 30})); err != nil {
+			t.Fatalf("Insert failed: %v", err)
+		}
+	}
+
+	type page struct {
+		ID    int64 `db:"id"`
+		Total int64 `db:"total"`
+	}
+	rows, err := SelectAll[page](conn, BuildSelect(Sqlite, "users", "id").
+		WithTotalCount("total").
+		OrderBy("id", "ASC", map[string]bool{"id": true}).
+		Limit(2).
+		Offset(2))
+	if err != nil {
+		t.Fatalf("SelectAll failed: %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("Expected 2 rows, got %d", len(rows))
+	}
+	for _, row := range rows {
+		if row.Total != 5 {
+			t.Errorf("Expected total 5 on row %d, got %d", row.ID, row.Total)
+		}
+	}
+
+	// Registered dialects have no capabilities
+	legacy := DBType("legacy")
+	if err := RegisterDialect(legacy, baseDialect{}); err != nil {
+		t.Fatalf("RegisterDialect failed: %v", err)
+	}
+	defer func() {
+		dialectsMu.Lock()
+		delete(dialects, legacy)
+		dialectsMu.Unlock()
+	}()
+
+	_, _, err = BuildSelect(legacy, "users").WithTotalCount("total").Build()
+	if !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Expected ErrUnsupported, got %v", err)
+	}
+}
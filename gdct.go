@@ -1,6 +1,7 @@
 package gdct
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"time"
@@ -8,21 +9,37 @@ import (
 
 // DBConfig holds database connection configuration.
 type DBConfig struct {
-	UserName     string         // Database username
-	Password     string         // Database password
-	Host         string         // Database host
-	Port         int            // Database port
-	Database     string         // Database name or file path for SQLite
-	SslMode      *string        // SSL mode for PostgreSQL
-	MaxLifeTime  *time.Duration // Maximum connection lifetime
-	MaxIdleConns *int           // Maximum idle connections
-	MaxOpenConns *int           // Maximum open connections
+	UserName        string         // Database username
+	Password        string         // Database password
+	Host            string         // Database host
+	Port            int            // Database port
+	Database        string         // Database name or file path for SQLite
+	SslMode         *string        // SSL mode for PostgreSQL
+	ApplicationName string         // application_name reported in pg_stat_activity (PostgreSQL only)
+	SearchPath      string         // Comma-separated schema search_path (PostgreSQL only)
+	Charset         string         // Connection charset, defaults to utf8mb4 (MariaDB/MySQL only)
+	Collation       string         // Connection collation (MariaDB/MySQL only)
+	Loc             string         // Time zone location for parsed times, e.g. "UTC" (MariaDB/MySQL only)
+	MaxLifeTime     *time.Duration // Maximum connection lifetime
+	MaxIdleConns    *int           // Maximum idle connections
+	MaxOpenConns    *int           // Maximum open connections
+
+	Logger             QueryLogger    // Hook invoked after each query execution
+	SlowQueryThreshold *time.Duration // Executions exceeding this duration are logged as slow
+
+	OnConnect func(*sql.Conn) error // Hook run on every new physical connection, e.g. for SET or PRAGMA statements
+
+	TimeFormat string // Layout for time.Time arguments such as SqliteTimeFormat, or TimeFormatUnix for Unix seconds; empty leaves them to the driver (SQLite only)
 }
 
 // DataBaseConnector wraps sql.DB with additional functionality.
 type DataBaseConnector struct {
 	*sql.DB
-	dbType DBType // Store database type for query building
+	dbType             DBType          // Store database type for query building
+	logger             QueryLogger     // Query logging hook
+	slowQueryThreshold *time.Duration  // Slow query logging threshold
+	inFlight           inFlight        // Running queries awaited by CloseGracefully
+	sqliteReturning    sqliteReturning // RETURNING support of the SQLite library, checked once
 }
 
 // PreparedQuery represents a prepared SQL query with parameters.
@@ -47,6 +64,78 @@ func InitConnection(dbType DBType, cfg DBConfig) (*DataBaseConnector, error) {
 	}
 }
 
+// newConnector wraps an opened sql.DB with the connector settings from cfg.
+func newConnector(db *sql.DB, dbType DBType, cfg DBConfig) *DataBaseConnector {
+	return &DataBaseConnector{
+		DB:                 db,
+		dbType:             dbType,
+		logger:             cfg.Logger,
+		slowQueryThreshold: cfg.SlowQueryThreshold,
+	}
+}
+
+// QueryContext executes a query that returns rows and reports it to the query logger.
+func (connect *DataBaseConnector) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	if err := connect.inFlight.begin(); err != nil {
+		return nil, err
+	}
+	defer connect.inFlight.end()
+
+	start := time.Now()
+	rows, err := connect.DB.QueryContext(ctx, query, args...)
+	connect.logQuery(query, args, time.Since(start), err)
+	return rows, err
+}
+
+// Query executes a query that returns rows and reports it to the query logger.
+func (connect *DataBaseConnector) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return connect.QueryContext(context.Background(), query, args...)
+}
+
+// QueryRowContext executes a query that returns at most one row and reports it to the query logger.
+func (connect *DataBaseConnector) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	// A *sql.Row cannot carry ErrConnectorClosing, so the query is tracked but never rejected
+	if connect.inFlight.begin() == nil {
+		defer connect.inFlight.end()
+	}
+
+	start := time.Now()
+	row := connect.DB.QueryRowContext(ctx, query, args...)
+	connect.logQuery(query, args, time.Since(start), row.Err())
+	return row
+}
+
+// QueryRow executes a query that returns at most one row and reports it to the query logger.
+func (connect *DataBaseConnector) QueryRow(query string, args ...interface{}) *sql.Row {
+	return connect.QueryRowContext(context.Background(), query, args...)
+}
+
+// ExecContext executes a query without returning rows and reports it to the query logger.
+func (connect *DataBaseConnector) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if err := connect.inFlight.begin(); err != nil {
+		return nil, err
+	}
+	defer connect.inFlight.end()
+
+	start := time.Now()
+	result, err := connect.DB.ExecContext(ctx, query, args...)
+	connect.logQuery(query, args, time.Since(start), err)
+	return result, err
+}
+
+// Exec executes a query without returning rows and reports it to the query logger.
+func (connect *DataBaseConnector) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return connect.ExecContext(context.Background(), query, args...)
+}
+
+// CheckConnectionContext checks the database connection, honoring the context deadline and cancellation.
+func (connect *DataBaseConnector) CheckConnectionContext(ctx context.Context) error {
+	if err := connect.PingContext(ctx); err != nil {
+		return fmt.Errorf("%s ping error: %w", connect.dbType, err)
+	}
+	return nil
+}
+
 // QueryBuilderRows executes a query that returns multiple rows.
 // Note: Caller is responsible for closing the returned *sql.Rows.
 func (connect *DataBaseConnector) QueryBuilderRows(queryString string, args []interface{}) (*sql.Rows, error) {
@@ -0,0 +1,90 @@
+package gdct
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync/atomic"
+)
+
+// DefaultIdentifierPattern is the pattern table and column names must match by default.
+const DefaultIdentifierPattern = `^[A-Za-z_][A-Za-z0-9_$.]*$`
+
+// ErrInvalidIdentifier is returned for table or column names rejected by the identifier pattern.
+var ErrInvalidIdentifier = fmt.Errorf("invalid identifier")
+
+var identifierPattern atomic.Pointer[regexp.Regexp]
+
+func init() {
+	identifierPattern.Store(regexp.MustCompile(DefaultIdentifierPattern))
+}
+
+/*
+SetIdentifierPattern
+
+@ pattern: Regular expression identifiers must match, empty restores DefaultIdentifierPattern
+@ Return: Error if the pattern does not compile
+*/
+func SetIdentifierPattern(pattern string) error {
+	if pattern == "" {
+		pattern = DefaultIdentifierPattern
+	}
+	compiled, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("compile identifier pattern error: %w", err)
+	}
+	identifierPattern.Store(compiled)
+	return nil
+}
+
+/*
+ValidateIdentifier
+
+@ name: Table or column name
+@ Return: Error if the name is empty or does not match the identifier pattern
+
+"*" and qualified wildcards such as "u.*" are allowed.
+Names containing semicolons, spaces or comment markers are rejected by the default pattern.
+*/
+func ValidateIdentifier(name string) error {
+	if name == "" {
+		return ErrEmptyIdentifier
+	}
+	if name == "*" {
+		return nil
+	}
+	if strings.HasSuffix(name, ".*") {
+		name = strings.TrimSuffix(name, ".*")
+	}
+	if !identifierPattern.Load().MatchString(name) {
+		return fmt.Errorf("%w: %q", ErrInvalidIdentifier, name)
+	}
+	return nil
+}
+
+// escapeAliased escapes a name that may carry an alias ("users u" or "users AS u").
+func escapeAliased(dialect Dialect, name string) (string, error) {
+	fields := strings.Fields(name)
+	var alias, separator string
+	switch {
+	case len(fields) == 2:
+		alias, separator = fields[1], " "
+	case len(fields) == 3 && strings.EqualFold(fields[1], "AS"):
+		alias, separator = fields[2], " AS "
+	default:
+		return dialect.EscapeIdentifier(name)
+	}
+
+	safeName, err := dialect.EscapeIdentifier(fields[0])
+	if err != nil {
+		return "", err
+	}
+	if strings.Contains(alias, ".") {
+		return "", fmt.Errorf("%w: alias %q", ErrInvalidIdentifier, alias)
+	}
+	safeAlias, err := dialect.EscapeIdentifier(alias)
+	if err != nil {
+		return "", err
+	}
+	return safeName + separator + safeAlias, nil
+}
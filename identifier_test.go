@@ -0,0 +1,131 @@
+package gdct
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateIdentifier(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected error
+	}{
+		{"Plain", "users", nil},
+		{"Qualified", "public.users", nil},
+		{"Wildcard", "*", nil},
+		{"Qualified wildcard", "u.*", nil},
+		{"Empty", "", ErrEmptyIdentifier},
+		{"Semicolon", "users; DROP TABLE users", ErrInvalidIdentifier},
+		{"Space", "user name", ErrInvalidIdentifier},
+		{"Comment", "users--", ErrInvalidIdentifier},
+		{"Leading digit", "1users", ErrInvalidIdentifier},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateIdentifier(tt.input)
+			if !errors.Is(err, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestIdentifierRejectedByBuilder(t *testing.T) {
+	_, _, err := BuildSelect(PostgreSQL, "users;").Build()
+	if !errors.Is(err, ErrInvalidIdentifier) {
+		t.Errorf("Expected ErrInvalidIdentifier for table with semicolon, got %v", err)
+	}
+
+	_, _, err = BuildSelect(PostgreSQL, "users").WhereIn("first name", []interface{}{"John"}).Build()
+	if !errors.Is(err, ErrInvalidIdentifier) {
+		t.Errorf("Expected ErrInvalidIdentifier for column with space, got %v", err)
+	}
+
+	_, _, err = BuildSelect(PostgreSQL, "users u; --").Build()
+	if err == nil {
+		t.Errorf("Expected error for table with trailing comment")
+	}
+
+	query, _, err := BuildSelect(PostgreSQL, "users u", "u.id", "u.name AS username").Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := "SELECT u.id, u.name AS username FROM users u"
+	if query != expected {
+		t.Errorf("Expected %q, got %q", expected, query)
+	}
+}
+
+func TestSetIdentifierPattern(t *testing.T) {
+	if err := SetIdentifierPattern(`^[a-z_]+$`); err != nil {
+		t.Fatalf("SetIdentifierPattern failed: %v", err)
+	}
+	defer SetIdentifierPattern("")
+
+	if err := ValidateIdentifier("Users"); !errors.Is(err, ErrInvalidIdentifier) {
+		t.Errorf("Expected ErrInvalidIdentifier with custom pattern, got %v", err)
+	}
+
+	if err := SetIdentifierPattern("("); err == nil {
+		t.Errorf("Expected error for invalid pattern")
+	}
+}
+
+func TestSelectRaw(t *testing.T) {
+	query, args, err := BuildSelect(PostgreSQL, "orders", "user_id").
+		SelectRaw("SUM(amount) FILTER (WHERE status = ?) AS paid", "paid").
+		Where("created_at > ?", "2024-01-01").
+		GroupBy("user_id").
+		Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "SELECT user_id, SUM(amount) FILTER (WHERE status = $1) AS paid FROM orders WHERE created_at > $2 GROUP BY user_id"
+	if query != expected {
+		t.Errorf("Expected %q, got %q", expected, query)
+	}
+	if len(args) != 2 || args[0] != "paid" || args[1] != "2024-01-01" {
+		t.Errorf("Expected args [paid 2024-01-01], got %v", args)
+	}
+}
+
+func TestAlias(t *testing.T) {
+	query, args, err := BuildSelect(PostgreSQL, "users", "u.id", "p.title").
+		Alias("u").
+		LeftJoin("posts AS p", "p.user_id = u.id").
+		Where("u.age > ?", 18).
+		Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "SELECT u.id, p.title FROM users AS u LEFT JOIN posts AS p ON p.user_id = u.id WHERE u.age > $1"
+	if query != expected {
+		t.Errorf("Expected %q, got %q", expected, query)
+	}
+	if len(args) != 1 || args[0] != 18 {
+		t.Errorf("Expected args [18], got %v", args)
+	}
+
+	query, _, err = BuildDelete(PostgreSQL, "users").Alias("u").Where("u.id = ?", 1).Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected = "DELETE FROM users AS u WHERE u.id = $1"
+	if query != expected {
+		t.Errorf("Expected %q, got %q", expected, query)
+	}
+
+	_, _, err = BuildSelect(PostgreSQL, "users u").Alias("x").Build()
+	if err == nil {
+		t.Errorf("Expected error for aliasing an aliased table")
+	}
+
+	_, _, err = BuildSelect(PostgreSQL, "users").Alias("u;").Build()
+	if !errors.Is(err, ErrInvalidIdentifier) {
+		t.Errorf("Expected ErrInvalidIdentifier for invalid alias, got %v", err)
+	}
+}
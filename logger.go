@@ -0,0 +1,55 @@
+package gdct
+
+import (
+	"log"
+	"time"
+)
+
+// LogLevel classifies a query log entry.
+type LogLevel int
+
+const (
+	LogLevelQuery LogLevel = iota // Regular query execution
+	LogLevelSlow                  // Execution exceeded DBConfig.SlowQueryThreshold
+)
+
+// String returns the string representation of LogLevel.
+func (l LogLevel) String() string {
+	switch l {
+	case LogLevelSlow:
+		return "SLOW"
+	default:
+		return "QUERY"
+	}
+}
+
+// QueryLog describes a single query execution.
+type QueryLog struct {
+	Level    LogLevel      // Log level of the entry
+	Query    string        // Executed SQL query
+	Args     []interface{} // Query parameters
+	Duration time.Duration // Time spent executing the query
+	Err      error         // Execution error, if any
+}
+
+// QueryLogger is a hook invoked after each query executed through DataBaseConnector.
+type QueryLogger func(entry QueryLog)
+
+// logQuery reports an executed query to the configured logger.
+// Queries exceeding the slow query threshold are reported with LogLevelSlow,
+// falling back to the standard logger when no QueryLogger is configured.
+func (connect *DataBaseConnector) logQuery(query string, args []interface{}, duration time.Duration, err error) {
+	level := LogLevelQuery
+	if connect.slowQueryThreshold != nil && duration > *connect.slowQueryThreshold {
+		level = LogLevelSlow
+	}
+
+	if connect.logger != nil {
+		connect.logger(QueryLog{Level: level, Query: query, Args: args, Duration: duration, Err: err})
+		return
+	}
+
+	if level == LogLevelSlow {
+		log.Printf("[SLOW_QUERY] %s took %s (threshold %s)", query, duration, *connect.slowQueryThreshold)
+	}
+}
@@ -0,0 +1,63 @@
+package gdct
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSlowQueryLogging(t *testing.T) {
+	threshold := time.Microsecond
+	var entries []QueryLog
+
+	conn := openTestSqlite(t, DBConfig{
+		Logger:             func(entry QueryLog) { entries = append(entries, entry) },
+		SlowQueryThreshold: &threshold,
+	})
+
+	slowQuery := `WITH RECURSIVE counter(x) AS (
+		SELECT 1 UNION ALL SELECT x + 1 FROM counter WHERE x < 200000
+	) SELECT COUNT(*) FROM counter`
+
+	var count int
+	if err := conn.QueryRow(slowQuery).Scan(&count); err != nil {
+		t.Fatalf("Slow query failed: %v", err)
+	}
+	if _, err := conn.Exec(slowQuery); err != nil {
+		t.Fatalf("Slow exec failed: %v", err)
+	}
+
+	var slow []QueryLog
+	for _, entry := range entries {
+		if entry.Level == LogLevelSlow {
+			slow = append(slow, entry)
+		}
+	}
+
+	if len(slow) == 0 {
+		t.Fatalf("Expected slow query to be logged, got entries %v", entries)
+	}
+	if slow[0].Query != slowQuery {
+		t.Errorf("Expected slow query text %q, got %q", slowQuery, slow[0].Query)
+	}
+	if slow[0].Duration <= threshold {
+		t.Errorf("Expected duration above %s, got %s", threshold, slow[0].Duration)
+	}
+}
+
+func TestQueryLoggingBelowThreshold(t *testing.T) {
+	threshold := time.Hour
+	var entries []QueryLog
+
+	conn := openTestSqlite(t, DBConfig{
+		Logger:             func(entry QueryLog) { entries = append(entries, entry) },
+		SlowQueryThreshold: &threshold,
+	})
+
+	if _, err := conn.Exec("SELECT 1"); err != nil {
+		t.Fatalf("Exec failed: %v", err)
+	}
+
+	if len(entries) != 1 || entries[0].Level != LogLevelQuery {
+		t.Errorf("Expected one regular query log entry, got %v", entries)
+	}
+}
@@ -4,29 +4,20 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
-	"log"
 
 	_ "github.com/go-sql-driver/mysql"
 )
 
 // InitMariadbConnection initializes a MariaDB/MySQL database connection.
 func InitMariadbConnection(dbType string, cfg DBConfig) (*DataBaseConnector, error) {
-	dbUrl := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s",
-		cfg.UserName,
-		cfg.Password,
-		cfg.Host,
-		cfg.Port,
-		cfg.Database,
-	)
+	cfg = decideDefaultConfigs(cfg, MariaDB)
 
-	db, err := sql.Open(dbType, dbUrl)
+	db, err := openDB(dbType, buildMariadbDSN(cfg), cfg.OnConnect, nil)
 
 	if err != nil {
 		return nil, fmt.Errorf("mariadb open connection error: %w", err)
 	}
 
-	cfg = decideDefaultConfigs(cfg, MariaDB)
-
 	if cfg.MaxOpenConns != nil {
 		db.SetMaxOpenConns(*cfg.MaxOpenConns)
 	}
@@ -37,17 +28,14 @@ func InitMariadbConnection(dbType string, cfg DBConfig) (*DataBaseConnector, err
 		db.SetConnMaxLifetime(*cfg.MaxLifeTime)
 	}
 
-	connect := &DataBaseConnector{DB: db, dbType: MariaDB}
+	connect := newConnector(db, MariaDB, cfg)
 
 	return connect, nil
 }
 
 // MrCheckConnection checks the MariaDB/MySQL database connection.
 func (connect *DataBaseConnector) MrCheckConnection() error {
-	// log.Printf("Waiting for Database Connection,,,")
-	// time.Sleep(time.Second * 10)
-
-	pingErr := connect.Ping()
+	pingErr := pingWithTimeout(connect)
 
 	if pingErr != nil {
 		return fmt.Errorf("mariadb ping error: %w", pingErr)
@@ -56,34 +44,9 @@ func (connect *DataBaseConnector) MrCheckConnection() error {
 	return nil
 }
 
+// MrCreateTable creates tables using transaction for MariaDB/MySQL
 func (connect *DataBaseConnector) MrCreateTable(queryList []string) error {
-	ctx := context.Background()
-
-	tx, txErr := connect.Begin()
-
-	if txErr != nil {
-		return fmt.Errorf("bigin transaction error: %w", txErr)
-	}
-
-	defer func() {
-		if txErr := tx.Rollback(); txErr != nil && txErr != sql.ErrTxDone {
-			log.Printf("[CREATE_TABLE] Transaction rollback error: %v", txErr)
-		}
-	}()
-
-	for _, queryString := range queryList {
-		_, execErr := tx.ExecContext(ctx, queryString)
-
-		if execErr != nil {
-			return fmt.Errorf("exec transaction context error: %w", execErr)
-		}
-	}
-
-	if commitErr := tx.Commit(); commitErr != nil {
-		return fmt.Errorf("commit transaction error: %w", commitErr)
-	}
-
-	return nil
+	return connect.CreateTableContext(context.Background(), queryList)
 }
 
 // MrSelectMultiple executes a query that returns multiple rows.
@@ -144,129 +107,15 @@ func (connect *DataBaseConnector) MrDeleteQuery(queryString string, args []inter
 
 // MrInsertMultiple executes multiple INSERT queries within a transaction.
 func (connect *DataBaseConnector) MrInsertMultiple(queryList []PreparedQuery) ([]sql.Result, error) {
-	ctx := context.Background()
-
-	tx, txErr := connect.Begin()
-
-	if txErr != nil {
-		return nil, fmt.Errorf("begin transaction error: %w", txErr)
-	}
-
-	defer func() {
-		_ = tx.Rollback()
-	}()
-
-	var txResultList []sql.Result
-
-	for _, query := range queryList {
-		// Prepared statement
-		stmt, prepareErr := tx.PrepareContext(ctx, query.Query)
-		if prepareErr != nil {
-			return nil, fmt.Errorf("prepare statement error: %w", prepareErr)
-		}
-
-		// PreparedStatement
-		txResult, execErr := stmt.ExecContext(ctx, query.Params...)
-
-		// Statement
-		stmt.Close()
-
-		if execErr != nil {
-			return nil, fmt.Errorf("exec prepared statement error: %w", execErr)
-		}
-
-		txResultList = append(txResultList, txResult)
-	}
-
-	if commitErr := tx.Commit(); commitErr != nil {
-		return nil, fmt.Errorf("commit transaction error: %w", commitErr)
-	}
-
-	return txResultList, nil
+	return connect.ExecMultipleTx(context.Background(), nil, queryList)
 }
 
 // MrUpdateMultiple executes multiple UPDATE queries within a transaction.
 func (connect *DataBaseConnector) MrUpdateMultiple(queryList []PreparedQuery) ([]sql.Result, error) {
-	ctx := context.Background()
-
-	tx, txErr := connect.Begin()
-
-	if txErr != nil {
-		return nil, fmt.Errorf("begin transaction error: %w", txErr)
-	}
-
-	defer func() {
-		_ = tx.Rollback()
-	}()
-
-	var txResultList []sql.Result
-
-	for _, query := range queryList {
-		// Prepared statement
-		stmt, prepareErr := tx.PrepareContext(ctx, query.Query)
-		if prepareErr != nil {
-			return nil, fmt.Errorf("prepare statement error: %w", prepareErr)
-		}
-
-		// PreparedStatement
-		txResult, execErr := stmt.ExecContext(ctx, query.Params...)
-
-		// Statement
-		stmt.Close()
-
-		if execErr != nil {
-			return nil, fmt.Errorf("exec prepared statement error: %w", execErr)
-		}
-
-		txResultList = append(txResultList, txResult)
-	}
-
-	if commitErr := tx.Commit(); commitErr != nil {
-		return nil, fmt.Errorf("commit transaction error: %w", commitErr)
-	}
-
-	return txResultList, nil
+	return connect.ExecMultipleTx(context.Background(), nil, queryList)
 }
 
 // MrDeleteMultiple executes multiple DELETE queries within a transaction.
 func (connect *DataBaseConnector) MrDeleteMultiple(queryList []PreparedQuery) ([]sql.Result, error) {
-	ctx := context.Background()
-
-	tx, txErr := connect.Begin()
-
-	if txErr != nil {
-		return nil, fmt.Errorf("begin transaction error: %w", txErr)
-	}
-
-	defer func() {
-		_ = tx.Rollback()
-	}()
-
-	var txResultList []sql.Result
-
-	for _, query := range queryList {
-		// Prepared statement
-		stmt, prepareErr := tx.PrepareContext(ctx, query.Query)
-		if prepareErr != nil {
-			return nil, fmt.Errorf("prepare statement error: %w", prepareErr)
-		}
-
-		// PreparedStatement
-		txResult, execErr := stmt.ExecContext(ctx, query.Params...)
-
-		// Statement
-		stmt.Close()
-
-		if execErr != nil {
-			return nil, fmt.Errorf("exec prepared statement error: %w", execErr)
-		}
-
-		txResultList = append(txResultList, txResult)
-	}
-
-	if commitErr := tx.Commit(); commitErr != nil {
-		return nil, fmt.Errorf("commit transaction error: %w", commitErr)
-	}
-
-	return txResultList, nil
+	return connect.ExecMultipleTx(context.Background(), nil, queryList)
 }
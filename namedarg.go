@@ -0,0 +1,104 @@
+package gdct
+
+// NamedArg is an argument referenced by :name tokens in conditions.
+// Every reference to the same name within one condition shares a single bind argument when the dialect numbers its placeholders.
+type NamedArg struct {
+	Name  string
+	Value interface{}
+}
+
+/*
+Arg
+
+@ name: Name referenced as :name in the condition
+@ value: Argument value
+@ Return: NamedArg to pass as a condition argument
+
+	qb.Where("a = :x OR b = :x", gdct.Arg("x", 5)) // a = $1 OR b = $1
+*/
+func Arg(name string, value interface{}) NamedArg {
+	return NamedArg{Name: name, Value: value}
+}
+
+func hasNamedArgs(args []interface{}) bool {
+	for _, arg := range args {
+		if _, ok := arg.(NamedArg); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// writeNamedClause renders a clause mixing "?" placeholders and :name tokens.
+// "::" casts, quoted sections and :name tokens without a matching NamedArg are written unchanged.
+func (w *queryWriter) writeNamedClause(c sqlClause) {
+	// Names are scoped to the clause, so another condition may reuse a name with a different value
+	w.named = nil
+	named := make(map[string]interface{})
+	var positional []interface{}
+	for _, arg := range c.args {
+		if na, ok := arg.(NamedArg); ok {
+			named[na.Name] = na.Value
+		} else {
+			positional = append(positional, arg)
+		}
+	}
+
+	s := c.sql
+	for i := 0; i < len(s); {
+		switch {
+		case s[i] == '\'' || s[i] == '"':
+			end := skipQuoted(s, i)
+			w.WriteString(s[i:end])
+			i = end
+		case s[i] == '?' && i+1 < len(s) && s[i+1] == '?':
+			w.WriteString(escapedQuestion(w.dialect))
+			i += 2
+		case s[i] == '?' && len(positional) > 0:
+			w.WriteString(w.bind(positional[0]))
+			positional = positional[1:]
+			i++
+		case s[i] == ':' && i+1 < len(s) && isNameStart(s[i+1]) && (i == 0 || s[i-1] != ':'):
+			end := i + 1
+			for end < len(s) && isNamePart(s[end]) {
+				end++
+			}
+			name := s[i+1 : end]
+			if value, ok := named[name]; ok {
+				w.WriteString(w.bindNamed(name, value))
+			} else {
+				w.WriteString(s[i:end])
+			}
+			i = end
+		default:
+			w.sql.WriteByte(s[i])
+			i++
+		}
+	}
+	w.args = append(w.args, positional...)
+}
+
+// bindNamed binds a named argument once and reuses its placeholder for later references.
+// Dialects with positional "?" placeholders bind the value again for every reference.
+func (w *queryWriter) bindNamed(name string, value interface{}) string {
+	if w.dialect.Placeholder(1) == w.dialect.Placeholder(2) {
+		return w.bind(value)
+	}
+	if idx, ok := w.named[name]; ok {
+		return w.dialect.Placeholder(idx)
+	}
+	if w.named == nil {
+		w.named = make(map[string]int)
+	}
+	placeholder := w.bind(value)
+	w.named[name] = len(w.args)
+	return placeholder
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isNamePart(c byte) bool {
+	return isNameStart(c) || (c >= '0' && c <= '9')
+}
@@ -0,0 +1,52 @@
+package gdct
+
+import "sync"
+
+// builderPool recycles QueryBuilder structs handed back through Release.
+var builderPool = sync.Pool{
+	New: func() interface{} {
+		return new(QueryBuilder)
+	},
+}
+
+// acquireBuilder returns an empty builder from the pool.
+func acquireBuilder() *QueryBuilder {
+	return builderPool.Get().(*QueryBuilder)
+}
+
+/*
+Reset
+
+Clears every setting of the builder, keeping the allocated slices for reuse.
+*/
+func (qb *QueryBuilder) Reset() {
+	// Drop references held by the slices so pooled builders do not keep arguments alive
+	clear(qb.columns)
+	clear(qb.joins)
+	clear(qb.conditions)
+	clear(qb.groupBy)
+	clear(qb.having)
+	clear(qb.args)
+	clear(qb.sets)
+
+	*qb = QueryBuilder{
+		columns:    qb.columns[:0],
+		joins:      qb.joins[:0],
+		conditions: qb.conditions[:0],
+		groupBy:    qb.groupBy[:0],
+		having:     qb.having[:0],
+		args:       qb.args[:0],
+		sets:       qb.sets[:0],
+	}
+}
+
+/*
+Release
+
+Resets the builder and returns it to the pool used by BuildSelect, BuildInsert, BuildUpdate and BuildDelete.
+The builder must not be used after Release. Query strings and arguments returned by Build stay valid.
+*/
+func (qb *QueryBuilder) Release() {
+	qb.Reset()
+	builderPool.Put(qb)
+}
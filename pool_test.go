@@ -0,0 +1,78 @@
+package gdct
+
+import "testing"
+
+func TestReleaseReuse(t *testing.T) {
+	qb := BuildSelect(PostgreSQL, "users", "id", "name").
+		LeftJoin("posts", "posts.user_id = users.id").
+		Where("age > ?", 18).
+		GroupBy("id").
+		Having("COUNT(*) > ?", 1).
+		SoftDelete("deleted_at").
+		Limit(10)
+
+	query, args, err := qb.Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	qb.Release()
+
+	// Results returned by Build stay valid after Release
+	expected := "SELECT id, name FROM users LEFT JOIN posts ON posts.user_id = users.id WHERE age > $1 AND users.deleted_at IS NULL GROUP BY id HAVING COUNT(*) > $2 LIMIT $3"
+	if query != expected {
+		t.Errorf("Expected %q, got %q", expected, query)
+	}
+	if len(args) != 3 || args[0] != 18 || args[1] != 1 || args[2] != int64(10) {
+		t.Errorf("Expected args [18 1 10], got %v", args)
+	}
+
+	for i := 0; i < 10; i++ {
+		next := BuildDelete(Mysql, "orders").Where("id = ?", i)
+		query, args, err := next.Build()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if query != "DELETE FROM orders WHERE id = ?" {
+			t.Errorf("Pooled builder leaked state: %q", query)
+		}
+		if len(args) != 1 || args[0] != i {
+			t.Errorf("Expected args [%d], got %v", i, args)
+		}
+		next.Release()
+	}
+}
+
+func TestReset(t *testing.T) {
+	qb := BuildUpdate(PostgreSQL, "users").
+		Set(map[string]interface{}{"name": "John"}).
+		Increment("logins", 1).
+		Where("id = ?", 1).
+		OptimisticLock("version", 2)
+	qb.Reset()
+
+	if qb.op != "" || qb.table != "" || qb.data != nil || qb.lockColumn != "" || qb.err != nil {
+		t.Errorf("Reset kept settings: %+v", qb)
+	}
+	if len(qb.conditions) != 0 || len(qb.sets) != 0 {
+		t.Errorf("Reset kept clauses: %+v", qb)
+	}
+	if cap(qb.conditions) > 0 && qb.conditions[:1][0].args != nil {
+		t.Errorf("Reset kept condition arguments")
+	}
+}
+
+func BenchmarkBuildSelectPooled(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		qb := BuildSelect(PostgreSQL, "users", "id", "name", "email").
+			Where("age > ?", 18).
+			Where("status = ?", "active").
+			OrderBy("created_at", "DESC", nil).
+			Limit(10)
+
+		if _, _, err := qb.Build(); err != nil {
+			b.Fatal(err)
+		}
+		qb.Release()
+	}
+}
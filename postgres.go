@@ -4,7 +4,6 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
-	"log"
 
 	_ "github.com/lib/pq"
 )
@@ -22,23 +21,20 @@ func (m *mockResult) RowsAffected() (int64, error) {
 
 // InitPostgresConnection initializes a PostgreSQL database connection.
 func InitPostgresConnection(dbType string, cfg DBConfig) (*DataBaseConnector, error) {
-	dbUrl := fmt.Sprintf("postgres://%s:%s@%s:%d/%s?sslmode=%s",
-		cfg.UserName,
-		cfg.Password,
-		cfg.Host,
-		cfg.Port,
-		cfg.Database,
-		*cfg.SslMode,
-	)
+	cfg = decideDefaultConfigs(cfg, PostgreSQL)
 
-	db, err := sql.Open(dbType, dbUrl)
+	if cfg.SearchPath != "" {
+		if err := validateSearchPath(cfg.SearchPath); err != nil {
+			return nil, fmt.Errorf("postgres config error: %w", err)
+		}
+	}
+
+	db, err := openDB(dbType, buildPostgresDSN(cfg), cfg.OnConnect, nil)
 
 	if err != nil {
 		return nil, fmt.Errorf("postgres open connection error: %w", err)
 	}
 
-	cfg = decideDefaultConfigs(cfg, PostgreSQL)
-
 	if cfg.MaxOpenConns != nil {
 		db.SetMaxOpenConns(*cfg.MaxOpenConns)
 	}
@@ -49,17 +45,14 @@ func InitPostgresConnection(dbType string, cfg DBConfig) (*DataBaseConnector, er
 		db.SetConnMaxLifetime(*cfg.MaxLifeTime)
 	}
 
-	connect := &DataBaseConnector{DB: db, dbType: PostgreSQL}
+	connect := newConnector(db, PostgreSQL, cfg)
 
 	return connect, nil
 }
 
 // PgCheckConnection checks the PostgreSQL database connection.
 func (connect *DataBaseConnector) PgCheckConnection() error {
-	// log.Printf("Waiting for Database Connection,,,")
-	// time.Sleep(time.Second * 10)
-
-	pingErr := connect.Ping()
+	pingErr := pingWithTimeout(connect)
 
 	if pingErr != nil {
 		return fmt.Errorf("postgres ping error: %w", pingErr)
@@ -68,32 +61,9 @@ func (connect *DataBaseConnector) PgCheckConnection() error {
 	return nil
 }
 
+// PgCreateTable creates tables using transaction for PostgreSQL
 func (connect *DataBaseConnector) PgCreateTable(queryList []string) error {
-	ctx := context.Background()
-
-	tx, txErr := connect.Begin()
-
-	if txErr != nil {
-		return fmt.Errorf("bigin transaction error: %w", txErr)
-	}
-
-	defer func() {
-		if txErr := tx.Rollback(); txErr != nil && txErr != sql.ErrTxDone {
-			log.Printf("[CREATE_TABLE] Transaction rollback error: %v", txErr)
-		}
-	}()
-
-	for _, queryString := range queryList {
-		if _, execErr := tx.ExecContext(ctx, queryString); execErr != nil {
-			return fmt.Errorf("exec transaction context error: %w", execErr)
-		}
-	}
-
-	if commitErr := tx.Commit(); commitErr != nil {
-		return fmt.Errorf("commit transaction error: %w", commitErr)
-	}
-
-	return nil
+	return connect.CreateTableContext(context.Background(), queryList)
 }
 
 // PgSelectMultiple executes a query that returns multiple rows.
@@ -167,129 +137,15 @@ func (connect *DataBaseConnector) PgDeleteQuery(queryString string, args []inter
 
 // PgInsertMultiple executes multiple INSERT queries within a transaction.
 func (connect *DataBaseConnector) PgInsertMultiple(queryList []PreparedQuery) ([]sql.Result, error) {
-	ctx := context.Background()
-
-	tx, txErr := connect.Begin()
-
-	if txErr != nil {
-		return nil, fmt.Errorf("begin transaction error: %w", txErr)
-	}
-
-	defer func() {
-		_ = tx.Rollback()
-	}()
-
-	var txResultList []sql.Result
-
-	for _, query := range queryList {
-		// Prepared statement
-		stmt, prepareErr := tx.PrepareContext(ctx, query.Query)
-		if prepareErr != nil {
-			return nil, fmt.Errorf("prepare statement error: %w", prepareErr)
-		}
-
-		// PreparedStatement
-		txResult, execErr := stmt.ExecContext(ctx, query.Params...)
-
-		// Statement
-		stmt.Close()
-
-		if execErr != nil {
-			return nil, fmt.Errorf("exec prepared statement error: %w", execErr)
-		}
-
-		txResultList = append(txResultList, txResult)
-	}
-
-	if commitErr := tx.Commit(); commitErr != nil {
-		return nil, fmt.Errorf("commit transaction error: %w", commitErr)
-	}
-
-	return txResultList, nil
+	return connect.ExecMultipleTx(context.Background(), nil, queryList)
 }
 
 // PgUpdateMultiple executes multiple UPDATE queries within a transaction.
 func (connect *DataBaseConnector) PgUpdateMultiple(queryList []PreparedQuery) ([]sql.Result, error) {
-	ctx := context.Background()
-
-	tx, txErr := connect.Begin()
-
-	if txErr != nil {
-		return nil, fmt.Errorf("begin transaction error: %w", txErr)
-	}
-
-	defer func() {
-		_ = tx.Rollback()
-	}()
-
-	var txResultList []sql.Result
-
-	for _, query := range queryList {
-		// Prepared statement
-		stmt, prepareErr := tx.PrepareContext(ctx, query.Query)
-		if prepareErr != nil {
-			return nil, fmt.Errorf("prepare statement error: %w", prepareErr)
-		}
-
-		// PreparedStatement
-		txResult, execErr := stmt.ExecContext(ctx, query.Params...)
-
-		// Statement
-		stmt.Close()
-
-		if execErr != nil {
-			return nil, fmt.Errorf("exec prepared statement error: %w", execErr)
-		}
-
-		txResultList = append(txResultList, txResult)
-	}
-
-	if commitErr := tx.Commit(); commitErr != nil {
-		return nil, fmt.Errorf("commit transaction error: %w", commitErr)
-	}
-
-	return txResultList, nil
+	return connect.ExecMultipleTx(context.Background(), nil, queryList)
 }
 
 // PgDeleteMultiple executes multiple DELETE queries within a transaction.
 func (connect *DataBaseConnector) PgDeleteMultiple(queryList []PreparedQuery) ([]sql.Result, error) {
-	ctx := context.Background()
-
-	tx, txErr := connect.Begin()
-
-	if txErr != nil {
-		return nil, fmt.Errorf("begin transaction error: %w", txErr)
-	}
-
-	defer func() {
-		_ = tx.Rollback()
-	}()
-
-	var txResultList []sql.Result
-
-	for _, query := range queryList {
-		// Prepared statement
-		stmt, prepareErr := tx.PrepareContext(ctx, query.Query)
-		if prepareErr != nil {
-			return nil, fmt.Errorf("prepare statement error: %w", prepareErr)
-		}
-
-		// PreparedStatement
-		txResult, execErr := stmt.ExecContext(ctx, query.Params...)
-
-		// Statement
-		stmt.Close()
-
-		if execErr != nil {
-			return nil, fmt.Errorf("exec prepared statement error: %w", execErr)
-		}
-
-		txResultList = append(txResultList, txResult)
-	}
-
-	if commitErr := tx.Commit(); commitErr != nil {
-		return nil, fmt.Errorf("commit transaction error: %w", commitErr)
-	}
-
-	return txResultList, nil
+	return connect.ExecMultipleTx(context.Background(), nil, queryList)
 }
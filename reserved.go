@@ -0,0 +1,51 @@
+package gdct
+
+import "strings"
+
+// commonReserved holds keywords reserved by every built-in database.
+var commonReserved = []string{
+	"all", "and", "as", "asc", "between", "by", "case", "check", "column", "constraint", "create",
+	"cross", "default", "delete", "desc", "distinct", "drop", "else", "exists", "foreign", "from",
+	"group", "having", "in", "inner", "insert", "into", "is", "join", "left", "like", "not", "null",
+	"on", "or", "order", "primary", "references", "right", "select", "set", "table", "then", "to",
+	"union", "unique", "update", "using", "values", "when", "where", "with",
+}
+
+// Reserved words are quoted by the dialects even though other identifiers are left bare.
+var (
+	postgresReserved  = reservedSet("user", "limit", "offset", "end", "window", "current_user", "session_user", "analyse", "analyze", "only")
+	mysqlReserved     = reservedSet("key", "keys", "index", "limit", "rank", "row_number", "groups", "window", "interval", "match", "range", "read", "condition", "div", "mod", "usage")
+	sqliteReserved    = reservedSet("index", "limit", "offset", "transaction", "temporary", "trigger", "view")
+	sqlserverReserved = reservedSet("user", "key", "index", "top", "percent", "offset", "fetch", "identity", "rowcount", "file", "plan", "transaction", "trigger", "view", "open", "close", "current_user", "session_user")
+)
+
+func reservedSet(words ...string) map[string]bool {
+	set := make(map[string]bool, len(commonReserved)+len(words))
+	for _, word := range commonReserved {
+		set[word] = true
+	}
+	for _, word := range words {
+		set[word] = true
+	}
+	return set
+}
+
+// quoteReserved quotes every dot-separated part of a validated identifier that is a reserved word.
+// fold lowercases quoted parts, so that PostgreSQL still resolves them like the unquoted name.
+func quoteReserved(name string, reserved map[string]bool, quote string, fold bool) string {
+	if !strings.Contains(name, ".") {
+		if !reserved[strings.ToLower(name)] {
+			return name
+		}
+		if fold {
+			name = strings.ToLower(name)
+		}
+		return quote + name + quote
+	}
+
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		parts[i] = quoteReserved(part, reserved, quote, fold)
+	}
+	return strings.Join(parts, ".")
+}
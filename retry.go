@@ -0,0 +1,122 @@
+package gdct
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math/rand"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+)
+
+var (
+	// retryBaseDelay is the backoff before the first retry, doubled on every further attempt.
+	retryBaseDelay = 20 * time.Millisecond
+	// retryMaxDelay caps the backoff between attempts.
+	retryMaxDelay = time.Second
+)
+
+// retryableSQLStates are the PostgreSQL SQLSTATE codes after which a transaction can be retried.
+var retryableSQLStates = map[pq.ErrorCode]bool{
+	"40001": true, // serialization_failure
+	"40P01": true, // deadlock_detected
+}
+
+// retryableMySQLErrors are the MariaDB/MySQL error numbers after which a transaction can be retried.
+var retryableMySQLErrors = map[uint16]bool{
+	1205: true, // ER_LOCK_WAIT_TIMEOUT
+	1213: true, // ER_LOCK_DEADLOCK
+}
+
+/*
+MySQLErrorCode
+
+@ err: Error returned by a MariaDB/MySQL query, possibly wrapped
+@ Return: MariaDB/MySQL error number and whether err carries one
+*/
+func MySQLErrorCode(err error) (uint16, bool) {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number, true
+	}
+	return 0, false
+}
+
+// IsRetryable reports whether err is a serialization failure, deadlock or lock wait timeout
+// that succeeds when the whole transaction is run again.
+func IsRetryable(err error) bool {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return retryableSQLStates[pqErr.Code]
+	}
+	if code, ok := MySQLErrorCode(err); ok {
+		return retryableMySQLErrors[code]
+	}
+	return false
+}
+
+/*
+WithRetryableTransaction
+
+@ ctx: Context for the transaction and the waits between attempts
+@ opts: Transaction options, typically with sql.LevelSerializable
+@ maxRetries: Number of times the transaction is run again after a retryable error
+@ fn: Function executed within the transaction, possibly several times
+@ Return: Error of the last attempt if any
+
+Runs fn in WithTransaction and starts over when the attempt fails with an IsRetryable error,
+waiting with exponential backoff between attempts. fn must not have side effects outside the transaction.
+*/
+func (connect *DataBaseConnector) WithRetryableTransaction(ctx context.Context, opts *sql.TxOptions, maxRetries int, fn func(*sql.Tx) error) error {
+	return retry(ctx, maxRetries, IsRetryable, func() error {
+		return connect.WithTransaction(ctx, opts, fn)
+	})
+}
+
+/*
+RetryOnDeadlock
+
+@ maxRetries: Number of times fn is run again after a deadlock
+@ fn: Write executed, possibly several times
+@ Return: Error of the last attempt if any
+
+Retries fn with jittered backoff while it fails with MariaDB/MySQL error 1213 (deadlock) or 1205 (lock wait timeout),
+as is common for writes contending on hot rows. Use MySQLErrorCode to inspect the returned error.
+A deadlock rolls back the whole transaction, so inside transactions use WithRetryableTransaction instead.
+*/
+func RetryOnDeadlock(maxRetries int, fn func() error) error {
+	return retry(context.Background(), maxRetries, func(err error) bool {
+		code, ok := MySQLErrorCode(err)
+		return ok && retryableMySQLErrors[code]
+	}, fn)
+}
+
+// retry runs fn until it succeeds, fails with an error shouldRetry rejects, or maxRetries retries are used up.
+func retry(ctx context.Context, maxRetries int, shouldRetry func(error) bool, fn func() error) error {
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= maxRetries || !shouldRetry(err) {
+			return err
+		}
+
+		timer := time.NewTimer(retryBackoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return errors.Join(err, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+// retryBackoff returns the wait before retry attempt+1: exponential growth with jitter,
+// so transactions that collided do not retry in lockstep.
+func retryBackoff(attempt int) time.Duration {
+	delay := retryMaxDelay
+	if attempt < 16 && retryBaseDelay<<attempt < retryMaxDelay {
+		delay = retryBaseDelay << attempt
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
@@ -0,0 +1,147 @@
+package gdct
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+)
+
+// fastRetries shortens the retry backoff for the duration of a test.
+func fastRetries(t *testing.T) {
+	t.Helper()
+	base, max := retryBaseDelay, retryMaxDelay
+	retryBaseDelay, retryMaxDelay = time.Millisecond, 2*time.Millisecond
+	t.Cleanup(func() { retryBaseDelay, retryMaxDelay = base, max })
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"postgres serialization failure", &pq.Error{Code: "40001"}, true},
+		{"postgres deadlock", &pq.Error{Code: "40P01"}, true},
+		{"postgres unique violation", &pq.Error{Code: "23505"}, false},
+		{"mysql deadlock", &mysql.MySQLError{Number: 1213}, true},
+		{"mysql lock wait timeout", &mysql.MySQLError{Number: 1205}, true},
+		{"mysql duplicate entry", &mysql.MySQLError{Number: 1062}, false},
+		{"wrapped", fmt.Errorf("commit transaction error: %w", &pq.Error{Code: "40001"}), true},
+		{"plain", errors.New("boom"), false},
+		{"nil", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsRetryable(tt.err); got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestWithRetryableTransaction(t *testing.T) {
+	fastRetries(t)
+	conn := openTestSqlite(t, DBConfig{})
+	createTestUsers(t, conn)
+	ctx := context.Background()
+
+	tests := []struct {
+		name             string
+		failures         int
+		failure          error
+		maxRetries       int
+		expectedAttempts int
+		expectErr        bool
+	}{
+		{"succeeds after retries", 2, &pq.Error{Code: "40001"}, 3, 3, false},
+		{"retries exhausted", 5, &mysql.MySQLError{Number: 1213}, 2, 3, true},
+		{"not retryable", 1, errors.New("constraint violation"), 3, 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			err := conn.WithRetryableTransaction(ctx, nil, tt.maxRetries, func(tx *sql.Tx) error {
+				attempts++
+				if _, err := tx.ExecContext(ctx, "INSERT INTO users (name) VALUES (?)", "retry"); err != nil {
+					return err
+				}
+				if attempts <= tt.failures {
+					return tt.failure
+				}
+				return nil
+			})
+
+			if attempts != tt.expectedAttempts {
+				t.Errorf("Expected %d attempts, got %d", tt.expectedAttempts, attempts)
+			}
+			if (err != nil) != tt.expectErr {
+				t.Errorf("Expected error %v, got %v", tt.expectErr, err)
+			}
+		})
+	}
+
+	// Only the successful attempt is committed
+	var count int
+	if err := conn.QueryRow("SELECT COUNT(*) FROM users WHERE name = ?", "retry").Scan(&count); err != nil {
+		t.Fatalf("QueryRow error: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Expected 1 committed row, got %d", count)
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	for attempt := 0; attempt < 40; attempt++ {
+		delay := retryBackoff(attempt)
+		if delay <= 0 || delay > retryMaxDelay {
+			t.Errorf("Attempt %d: backoff %s out of range", attempt, delay)
+		}
+	}
+}
+
+func TestRetryOnDeadlock(t *testing.T) {
+	fastRetries(t)
+
+	tests := []struct {
+		name             string
+		failures         int
+		failure          error
+		maxRetries       int
+		expectedAttempts int
+		expectedCode     uint16
+	}{
+		{"deadlock then success", 3, &mysql.MySQLError{Number: 1213}, 3, 4, 0},
+		{"lock wait timeout exhausted", 5, &mysql.MySQLError{Number: 1205}, 1, 2, 1205},
+		{"duplicate entry", 5, &mysql.MySQLError{Number: 1062}, 3, 1, 1062},
+		{"postgres deadlock", 5, &pq.Error{Code: "40P01"}, 3, 1, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			err := RetryOnDeadlock(tt.maxRetries, func() error {
+				attempts++
+				if attempts <= tt.failures {
+					return fmt.Errorf("exec update query error: %w", tt.failure)
+				}
+				return nil
+			})
+
+			if attempts != tt.expectedAttempts {
+				t.Errorf("Expected %d attempts, got %d", tt.expectedAttempts, attempts)
+			}
+			code, _ := MySQLErrorCode(err)
+			if code != tt.expectedCode {
+				t.Errorf("Expected error code %d, got %d (%v)", tt.expectedCode, code, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,210 @@
+package gdct
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+	timeType    = reflect.TypeOf(time.Time{})
+
+	// Cache of struct field indexes keyed by reflect.Type
+	fieldIndexCache sync.Map
+)
+
+// isScannable reports whether values of type t are scanned directly from a single column
+// instead of being mapped field by field.
+func isScannable(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct {
+		return true
+	}
+	return t == timeType || reflect.PointerTo(t).Implements(scannerType)
+}
+
+// fieldIndexes maps lower-cased column names to struct field indexes.
+// Columns are taken from the `db` tag, falling back to the field name.
+// Fields tagged `db:"-"` are skipped and embedded structs are flattened.
+func fieldIndexes(t reflect.Type) map[string][]int {
+	if cached, ok := fieldIndexCache.Load(t); ok {
+		return cached.(map[string][]int)
+	}
+
+	indexes := make(map[string][]int)
+	collectFieldIndexes(t, nil, indexes)
+
+	fieldIndexCache.Store(t, indexes)
+	return indexes
+}
+
+func collectFieldIndexes(t reflect.Type, parent []int, indexes map[string][]int) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("db")
+		if tag == "-" || (!field.IsExported() && !field.Anonymous) {
+			continue
+		}
+
+		index := append(append([]int{}, parent...), i)
+
+		if field.Anonymous && tag == "" && field.Type.Kind() == reflect.Struct && !isScannable(field.Type) {
+			collectFieldIndexes(field.Type, index, indexes)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		name := strings.Split(tag, ",")[0]
+		if name == "" {
+			name = field.Name
+		}
+
+		key := strings.ToLower(name)
+		if _, exists := indexes[key]; !exists {
+			indexes[key] = index
+		}
+	}
+}
+
+// scanTargets returns the scan destinations of the given columns within v.
+// Columns without a matching field are discarded.
+func scanTargets(v reflect.Value, columns []string) []interface{} {
+	targets := make([]interface{}, len(columns))
+
+	if isScannable(v.Type()) {
+		targets[0] = v.Addr().Interface()
+		for i := 1; i < len(columns); i++ {
+			targets[i] = new(interface{})
+		}
+		return targets
+	}
+
+	indexes := fieldIndexes(v.Type())
+	for i, col := range columns {
+		if index, ok := indexes[strings.ToLower(col)]; ok {
+			targets[i] = v.FieldByIndex(index).Addr().Interface()
+		} else {
+			targets[i] = new(interface{})
+		}
+	}
+	return targets
+}
+
+/*
+scanAll
+
+@ rows: Rows to map
+@ dest: Pointer to a slice of structs, struct pointers or scalar values
+@ Return: Error if any
+*/
+func scanAll(rows *sql.Rows, dest interface{}) error {
+	destValue := reflect.ValueOf(dest)
+	if destValue.Kind() != reflect.Pointer || destValue.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("scan destination must be a pointer to a slice, got %T", dest)
+	}
+
+	sliceValue := destValue.Elem()
+	elemType := sliceValue.Type().Elem()
+	isPtr := elemType.Kind() == reflect.Pointer
+	if isPtr {
+		elemType = elemType.Elem()
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return fmt.Errorf("get columns error: %w", err)
+	}
+
+	for rows.Next() {
+		elem := reflect.New(elemType)
+		if err := rows.Scan(scanTargets(elem.Elem(), columns)...); err != nil {
+			return fmt.Errorf("scan row error: %w", err)
+		}
+
+		if isPtr {
+			sliceValue.Set(reflect.Append(sliceValue, elem))
+		} else {
+			sliceValue.Set(reflect.Append(sliceValue, elem.Elem()))
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterate rows error: %w", err)
+	}
+
+	return nil
+}
+
+/*
+scanOne
+
+@ rows: Rows to map
+@ dest: Pointer to a struct or scalar value
+@ Return: sql.ErrNoRows when there is no row, error if any
+*/
+func scanOne(rows *sql.Rows, dest interface{}) error {
+	destValue := reflect.ValueOf(dest)
+	if destValue.Kind() != reflect.Pointer || destValue.IsNil() {
+		return fmt.Errorf("scan destination must be a non-nil pointer, got %T", dest)
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return fmt.Errorf("get columns error: %w", err)
+	}
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("iterate rows error: %w", err)
+		}
+		return sql.ErrNoRows
+	}
+
+	if err := rows.Scan(scanTargets(destValue.Elem(), columns)...); err != nil {
+		return fmt.Errorf("scan row error: %w", err)
+	}
+
+	return nil
+}
+
+/*
+scanMaps
+
+@ rows: Rows to map
+@ Return: One map per row keyed by column name, error if any
+*/
+func scanMaps(rows *sql.Rows) ([]map[string]interface{}, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("get columns error: %w", err)
+	}
+
+	var result []map[string]interface{}
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		targets := make([]interface{}, len(columns))
+		for i := range values {
+			targets[i] = &values[i]
+		}
+		if err := rows.Scan(targets...); err != nil {
+			return nil, fmt.Errorf("scan row error: %w", err)
+		}
+
+		row := make(map[string]interface{}, len(columns))
+		for i, column := range columns {
+			row[column] = values[i]
+		}
+		result = append(result, row)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate rows error: %w", err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,53 @@
+package gdct
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+var (
+	sessionVarNameRegexp  = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*$`)
+	sessionVarValueRegexp = regexp.MustCompile(`^[A-Za-z0-9_.,:/+-]+$`)
+)
+
+/*
+SetSessionVar
+
+@ name: Session variable, or pragma for SQLite
+@ value: Value to set, limited to letters, digits and _ . , : / + -
+@ Return: Error if any
+
+Runs SET name = 'value' on PostgreSQL, SET SESSION name = value on MariaDB/MySQL and PRAGMA name = value on SQLite.
+Session state belongs to a single pooled connection, so set MaxOpenConns to 1 when every query must see the variable.
+*/
+func (connect *DataBaseConnector) SetSessionVar(name, value string) error {
+	if !sessionVarNameRegexp.MatchString(name) {
+		return fmt.Errorf("invalid session variable name %q", name)
+	}
+	if !sessionVarValueRegexp.MatchString(value) {
+		return fmt.Errorf("invalid value %q for session variable %s", value, name)
+	}
+
+	var query string
+	switch connect.dbType {
+	case PostgreSQL:
+		query = fmt.Sprintf("SET %s = '%s'", name, value)
+	case MariaDB, Mysql:
+		// Numeric variables reject quoted values
+		if _, err := strconv.ParseFloat(value, 64); err == nil {
+			query = fmt.Sprintf("SET SESSION %s = %s", name, value)
+		} else {
+			query = fmt.Sprintf("SET SESSION %s = '%s'", name, value)
+		}
+	case Sqlite:
+		query = fmt.Sprintf("PRAGMA %s = %s", name, value)
+	default:
+		return fmt.Errorf("SetSessionVar() %w: %s", ErrUnsupported, connect.dbType)
+	}
+
+	if _, err := connect.Exec(query); err != nil {
+		return fmt.Errorf("set session variable %s error: %w", name, err)
+	}
+	return nil
+}
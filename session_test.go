@@ -0,0 +1,41 @@
+package gdct
+
+import "testing"
+
+func TestSetSessionVarSqlite(t *testing.T) {
+	openConns := 1
+	conn := openTestSqlite(t, DBConfig{MaxOpenConns: &openConns})
+
+	if err := conn.SetSessionVar("cache_size", "-4000"); err != nil {
+		t.Fatalf("SetSessionVar failed: %v", err)
+	}
+
+	var cacheSize int
+	if err := conn.QueryRow("PRAGMA cache_size").Scan(&cacheSize); err != nil {
+		t.Fatalf("Read pragma failed: %v", err)
+	}
+	if cacheSize != -4000 {
+		t.Errorf("Expected cache_size -4000, got %d", cacheSize)
+	}
+}
+
+func TestSetSessionVarValidation(t *testing.T) {
+	conn := openTestSqlite(t, DBConfig{})
+
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{"cache_size; DROP TABLE users", "1"},
+		{"cache_size", "1; DROP TABLE users"},
+		{"cache_size", "'1'"},
+		{"", "1"},
+		{"cache_size", ""},
+	}
+
+	for _, tt := range tests {
+		if err := conn.SetSessionVar(tt.name, tt.value); err == nil {
+			t.Errorf("Expected error for %q = %q", tt.name, tt.value)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package gdct
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrConnectorClosing is returned for work started after CloseGracefully was called.
+var ErrConnectorClosing = errors.New("database connector is closing")
+
+// idlePollInterval is how often CloseGracefully checks for connections still held by open rows or transactions.
+var idlePollInterval = 10 * time.Millisecond
+
+// inFlight counts the queries and transactions running through a DataBaseConnector.
+type inFlight struct {
+	mu      sync.Mutex
+	active  int
+	closing bool
+	idle    chan struct{} // Closed once active drops to zero while closing
+}
+
+// begin registers a new query, failing once the connector is closing.
+func (f *inFlight) begin() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closing {
+		return ErrConnectorClosing
+	}
+	f.active++
+	return nil
+}
+
+// end unregisters a query started with begin.
+func (f *inFlight) end() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.active--
+	if f.active == 0 && f.idle != nil {
+		close(f.idle)
+		f.idle = nil
+	}
+}
+
+// drain stops new work and returns a channel closed when no query is running.
+func (f *inFlight) drain() <-chan struct{} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.closing = true
+	if f.active == 0 {
+		done := make(chan struct{})
+		close(done)
+		return done
+	}
+	if f.idle == nil {
+		f.idle = make(chan struct{})
+	}
+	return f.idle
+}
+
+/*
+CloseGracefully
+
+@ ctx: Context bounding the wait for running queries
+@ Return: Error if any
+
+Rejects new queries and transactions with ErrConnectorClosing, waits for the running ones to finish and closes the database.
+Connections still held by open Rows, unscanned rows of QueryRow and transactions from Begin are awaited too.
+If ctx ends first the database is closed anyway and the context error is returned.
+*/
+func (connect *DataBaseConnector) CloseGracefully(ctx context.Context) error {
+	if err := connect.waitIdle(ctx); err != nil {
+		waitErr := fmt.Errorf("wait for in-flight queries error: %w", err)
+		return errors.Join(waitErr, connect.Close())
+	}
+	return connect.Close()
+}
+
+// waitIdle waits for the tracked queries, then for every connection the pool reports in use.
+func (connect *DataBaseConnector) waitIdle(ctx context.Context) error {
+	select {
+	case <-connect.inFlight.drain():
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	ticker := time.NewTicker(idlePollInterval)
+	defer ticker.Stop()
+	for connect.Stats().InUse > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
+
+// BeginTx starts a transaction, failing with ErrConnectorClosing once CloseGracefully was called.
+func (connect *DataBaseConnector) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	if err := connect.inFlight.begin(); err != nil {
+		return nil, err
+	}
+	defer connect.inFlight.end()
+	return connect.DB.BeginTx(ctx, opts)
+}
+
+// Begin starts a transaction, failing with ErrConnectorClosing once CloseGracefully was called.
+func (connect *DataBaseConnector) Begin() (*sql.Tx, error) {
+	return connect.BeginTx(context.Background(), nil)
+}
@@ -0,0 +1,120 @@
+package gdct
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// slowQuery counts to a few million so that it runs long enough to overlap CloseGracefully.
+const slowQuery = "WITH RECURSIVE n(x) AS (SELECT 1 UNION ALL SELECT x + 1 FROM n WHERE x < 1000000) SELECT COUNT(*) FROM n"
+
+func TestCloseGracefully(t *testing.T) {
+	// The logger runs before the query is unregistered, so finished is closed before CloseGracefully may return
+	finished := make(chan struct{})
+	conn := openTestSqlite(t, DBConfig{Logger: func(entry QueryLog) {
+		if entry.Query == slowQuery {
+			close(finished)
+		}
+	}})
+
+	queryErr := make(chan error, 1)
+	go func() {
+		_, err := conn.Exec(slowQuery)
+		queryErr <- err
+	}()
+
+	for {
+		conn.inFlight.mu.Lock()
+		active := conn.inFlight.active
+		conn.inFlight.mu.Unlock()
+		if active > 0 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := conn.CloseGracefully(ctx); err != nil {
+		t.Fatalf("CloseGracefully failed: %v", err)
+	}
+
+	select {
+	case <-finished:
+	default:
+		t.Errorf("Expected CloseGracefully to wait for the in-flight query")
+	}
+	if err := <-queryErr; err != nil {
+		t.Errorf("Expected in-flight query to succeed, got %v", err)
+	}
+
+	if _, err := conn.Exec("SELECT 1"); !errors.Is(err, ErrConnectorClosing) {
+		t.Errorf("Expected ErrConnectorClosing after close, got %v", err)
+	}
+}
+
+func TestCloseGracefullyTimeout(t *testing.T) {
+	conn := openTestSqlite(t, DBConfig{})
+
+	if err := conn.inFlight.begin(); err != nil {
+		t.Fatalf("begin failed: %v", err)
+	}
+	defer conn.inFlight.end()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := conn.CloseGracefully(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestCloseGracefullyWaitsForRowsAndTx(t *testing.T) {
+	conn := openTestSqlite(t, DBConfig{})
+	createTestUsers(t, conn)
+
+	rows, err := conn.Query("SELECT id FROM users")
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	tx, err := conn.Begin()
+	if err != nil {
+		t.Fatalf("Begin failed: %v", err)
+	}
+
+	closed := make(chan error, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		closed <- conn.CloseGracefully(ctx)
+	}()
+
+	// Wait for CloseGracefully to start draining before checking that new transactions are rejected
+	for {
+		conn.inFlight.mu.Lock()
+		closing := conn.inFlight.closing
+		conn.inFlight.mu.Unlock()
+		if closing {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if _, err := conn.Begin(); !errors.Is(err, ErrConnectorClosing) {
+		t.Errorf("Expected ErrConnectorClosing for Begin, got %v", err)
+	}
+
+	rows.Close()
+	select {
+	case err := <-closed:
+		t.Fatalf("Expected CloseGracefully to wait for the open transaction, returned %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+	if err := <-closed; err != nil {
+		t.Errorf("CloseGracefully failed: %v", err)
+	}
+}
@@ -0,0 +1,172 @@
+package gdct
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+)
+
+/*
+ExecFile
+
+@ ctx: Context for the transaction
+@ path: Path of the SQL script
+@ Return: Error if any
+
+Splits the script into statements on semicolons and executes them in a single transaction.
+MariaDB and Mysql scripts may escape quotes inside strings with a backslash.
+*/
+func (connect *DataBaseConnector) ExecFile(ctx context.Context, path string) error {
+	script, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read sql file error: %w", err)
+	}
+
+	return connect.WithTransaction(ctx, nil, func(tx *sql.Tx) error {
+		if err := execStatements(ctx, tx, connect.splitScript(string(script))); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		return nil
+	})
+}
+
+/*
+ExecFS
+
+@ ctx: Context for the transaction
+@ fsys: File system holding the SQL scripts, such as an embed.FS
+@ pattern: fs.Glob pattern selecting the scripts
+@ Return: Error if any
+
+The matching scripts run in lexical order within a single transaction, so either all of them apply or none does.
+*/
+func (connect *DataBaseConnector) ExecFS(ctx context.Context, fsys fs.FS, pattern string) error {
+	names, err := fs.Glob(fsys, pattern)
+	if err != nil {
+		return fmt.Errorf("glob sql files error: %w", err)
+	}
+	if len(names) == 0 {
+		return fmt.Errorf("no sql files match %q", pattern)
+	}
+
+	scripts := make([][]string, len(names))
+	for i, name := range names {
+		script, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return fmt.Errorf("read sql file error: %w", err)
+		}
+		scripts[i] = connect.splitScript(string(script))
+	}
+
+	return connect.WithTransaction(ctx, nil, func(tx *sql.Tx) error {
+		for i, statements := range scripts {
+			if err := execStatements(ctx, tx, statements); err != nil {
+				return fmt.Errorf("%s: %w", names[i], err)
+			}
+		}
+		return nil
+	})
+}
+
+/*
+SplitStatements
+
+@ script: SQL script holding several statements
+@ Return: Statements without their terminating semicolons
+
+Semicolons inside quoted strings and identifiers, comments and dollar-quoted bodies ($$ ... $$ or $tag$ ... $tag$) do not split.
+Empty statements are dropped. Quotes are only escaped by doubling them, as in standard SQL;
+ExecFile and ExecFS also accept backslash escapes for MariaDB and Mysql.
+*/
+func SplitStatements(script string) []string {
+	return splitStatements(script, false)
+}
+
+// splitScript splits a script with the string escaping rules of the connected database.
+func (connect *DataBaseConnector) splitScript(script string) []string {
+	return splitStatements(script, connect.dbType == MariaDB || connect.dbType == Mysql)
+}
+
+// splitStatements splits script on semicolons, treating a backslash inside quoted strings as an escape when backslashEscapes is set.
+func splitStatements(script string, backslashEscapes bool) []string {
+	var statements []string
+	start := 0
+
+	add := func(end int) {
+		if statement := strings.TrimSpace(script[start:end]); statement != "" {
+			statements = append(statements, statement)
+		}
+	}
+
+	for i := 0; i < len(script); i++ {
+		switch c := script[i]; {
+		case backslashEscapes && (c == '\'' || c == '"'):
+			i = skipBackslashQuoted(script, i) - 1
+		case c == '\'' || c == '"' || c == '`':
+			i = skipQuoted(script, i) - 1
+		case c == '-' && strings.HasPrefix(script[i:], "--"):
+			if end := strings.IndexByte(script[i:], '\n'); end >= 0 {
+				i += end
+			} else {
+				i = len(script)
+			}
+		case c == '/' && strings.HasPrefix(script[i:], "/*"):
+			if end := strings.Index(script[i+2:], "*/"); end >= 0 {
+				i += end + 3
+			} else {
+				i = len(script)
+			}
+		case c == '$':
+			if tag := dollarTag(script[i:]); tag != "" {
+				if end := strings.Index(script[i+len(tag):], tag); end >= 0 {
+					i += len(tag) + end + len(tag) - 1
+				} else {
+					i = len(script)
+				}
+			}
+		case c == ';':
+			add(i)
+			start = i + 1
+		}
+	}
+	if start < len(script) {
+		add(len(script))
+	}
+
+	return statements
+}
+
+// skipBackslashQuoted is skipQuoted for MariaDB and Mysql strings, where a backslash also escapes the next character.
+func skipBackslashQuoted(s string, i int) int {
+	quote := s[i]
+	for j := i + 1; j < len(s); j++ {
+		switch s[j] {
+		case '\\':
+			j++
+		case quote:
+			if j+1 < len(s) && s[j+1] == quote {
+				j++
+				continue
+			}
+			return j + 1
+		}
+	}
+	return len(s)
+}
+
+// dollarTag returns the PostgreSQL dollar-quote opening s ("$$" or "$tag$"), or "" when s does not start one.
+func dollarTag(s string) string {
+	for i := 1; i < len(s); i++ {
+		c := s[i]
+		if c == '$' {
+			return s[:i+1]
+		}
+		if !isNamePart(c) || (i == 1 && c >= '0' && c <= '9') {
+			return ""
+		}
+	}
+	return ""
+}
@@ -0,0 +1,112 @@
+package gdct
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestSplitStatements(t *testing.T) {
+	script := `-- users; with a comment
+CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT);
+INSERT INTO users (name) VALUES ('semi;colon'), ('it''s');
+/* block; comment */
+CREATE FUNCTION touch() RETURNS trigger AS $$
+BEGIN
+  NEW.updated_at = now();
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+SELECT $tag$a;b$tag$, $1;;
+`
+
+	expected := []string{
+		"-- users; with a comment\nCREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT)",
+		"INSERT INTO users (name) VALUES ('semi;colon'), ('it''s')",
+		"/* block; comment */\nCREATE FUNCTION touch() RETURNS trigger AS $$\nBEGIN\n  NEW.updated_at = now();\n  RETURN NEW;\nEND;\n$$ LANGUAGE plpgsql",
+		"SELECT $tag$a;b$tag$, $1",
+	}
+
+	got := SplitStatements(script)
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+}
+
+func TestSplitStatementsBackslashEscapes(t *testing.T) {
+	script := `INSERT INTO notes (body) VALUES ('it\'s; fine'), ("say \"hi;\""), ('c:\\');
+SELECT 1;`
+
+	expected := []string{
+		`INSERT INTO notes (body) VALUES ('it\'s; fine'), ("say \"hi;\""), ('c:\\')`,
+		"SELECT 1",
+	}
+	if got := splitStatements(script, true); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+
+	conn := &DataBaseConnector{dbType: Mysql}
+	if got := conn.splitScript(script); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected Mysql scripts to honor backslash escapes, got %q", got)
+	}
+
+	// Standard SQL keeps the backslash literal, so the quote closes the string
+	if got := SplitStatements(`SELECT 'c:\'; SELECT 2;`); len(got) != 2 {
+		t.Errorf("Expected 2 statements, got %q", got)
+	}
+}
+
+func TestExecFile(t *testing.T) {
+	conn := openTestSqlite(t, DBConfig{})
+
+	path := filepath.Join(t.TempDir(), "setup.sql")
+	script := `CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT);
+INSERT INTO users (name) VALUES ('John; Doe');
+INSERT INTO users (name) VALUES ('Jane');
+`
+	if err := os.WriteFile(path, []byte(script), 0o600); err != nil {
+		t.Fatalf("Write file failed: %v", err)
+	}
+
+	if err := conn.ExecFile(context.Background(), path); err != nil {
+		t.Fatalf("ExecFile failed: %v", err)
+	}
+
+	count, err := Count(conn, BuildSelect(Sqlite, "users"))
+	if err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("Expected 2 rows, got %d", count)
+	}
+}
+
+func TestExecFSRollback(t *testing.T) {
+	conn := openTestSqlite(t, DBConfig{})
+
+	fsys := fstest.MapFS{
+		"sql/001_users.sql": {Data: []byte("CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT);")},
+		"sql/002_seed.sql":  {Data: []byte("INSERT INTO users (name) VALUES ('John'); INSERT INTO missing (x) VALUES (1);")},
+	}
+
+	err := conn.ExecFS(context.Background(), fsys, "sql/*.sql")
+	if err == nil {
+		t.Fatalf("Expected error for missing table")
+	}
+
+	var tables int
+	if err := conn.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE name = 'users'").Scan(&tables); err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if tables != 0 {
+		t.Errorf("Expected every script to be rolled back")
+	}
+
+	delete(fsys, "sql/002_seed.sql")
+	if err := conn.ExecFS(context.Background(), fsys, "sql/*.sql"); err != nil {
+		t.Fatalf("ExecFS failed: %v", err)
+	}
+}
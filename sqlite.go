@@ -3,15 +3,28 @@ package gdct
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
+	"errors"
 	"fmt"
+	"sync"
+	"time"
 
 	_ "github.com/mattn/go-sqlite3"
 )
 
+const (
+	// SqliteTimeFormat is a DBConfig.TimeFormat layout for time.Time arguments on SQLite.
+	// Values are bound in UTC with fixed-width fractions, so text comparisons order them chronologically.
+	SqliteTimeFormat = "2006-01-02T15:04:05.000000000Z07:00"
+
+	// TimeFormatUnix binds time.Time arguments as Unix seconds instead of text.
+	TimeFormatUnix = "unix"
+)
+
 // InitSqliteConnection initializes SQLite database connection
 func InitSqliteConnection(dbType string, cfg DBConfig) (*DataBaseConnector, error) {
 	// For SQLite, the Database field should contain the file path
-	db, err := sql.Open(dbType, cfg.Database)
+	db, err := openDB(dbType, cfg.Database, cfg.OnConnect, wrapTimeFormat(cfg.TimeFormat))
 	if err != nil {
 		return nil, fmt.Errorf("sqlite open connection error: %w", err)
 	}
@@ -31,18 +44,18 @@ func InitSqliteConnection(dbType string, cfg DBConfig) (*DataBaseConnector, erro
 	}
 
 	// Test the connection
-	if err := db.Ping(); err != nil {
+	if err := pingWithTimeout(db); err != nil {
 		db.Close()
 		return nil, fmt.Errorf("sqlite ping error: %w", err)
 	}
 
-	connect := &DataBaseConnector{DB: db, dbType: Sqlite}
+	connect := newConnector(db, Sqlite, cfg)
 	return connect, nil
 }
 
 // SqCheckConnection checks SQLite database connection
 func (connect *DataBaseConnector) SqCheckConnection() error {
-	if err := connect.Ping(); err != nil {
+	if err := pingWithTimeout(connect); err != nil {
 		return fmt.Errorf("sqlite ping error: %w", err)
 	}
 	return nil
@@ -50,34 +63,7 @@ func (connect *DataBaseConnector) SqCheckConnection() error {
 
 // SqCreateTable creates tables using transaction for SQLite
 func (connect *DataBaseConnector) SqCreateTable(queryList []string) error {
-	ctx := context.Background()
-
-	tx, err := connect.BeginTx(ctx, nil)
-	if err != nil {
-		return fmt.Errorf("begin transaction error: %w", err)
-	}
-
-	defer func() {
-		if p := recover(); p != nil {
-			tx.Rollback()
-			panic(p)
-		} else if err != nil {
-			tx.Rollback()
-		}
-	}()
-
-	for _, queryString := range queryList {
-		if _, execErr := tx.ExecContext(ctx, queryString); execErr != nil {
-			err = fmt.Errorf("exec transaction context error: %w", execErr)
-			return err
-		}
-	}
-
-	if err = tx.Commit(); err != nil {
-		return fmt.Errorf("commit transaction error: %w", err)
-	}
-
-	return nil
+	return connect.CreateTableContext(context.Background(), queryList)
 }
 
 // SqSelectMultiple queries multiple rows from SQLite
@@ -132,136 +118,17 @@ func (connect *DataBaseConnector) SqDeleteQuery(queryString string, args []inter
 
 // SqInsertMultiple inserts multiple records with transaction
 func (connect *DataBaseConnector) SqInsertMultiple(queryList []PreparedQuery) ([]sql.Result, error) {
-	ctx := context.Background()
-
-	tx, err := connect.BeginTx(ctx, nil)
-	if err != nil {
-		return nil, fmt.Errorf("begin transaction error: %w", err)
-	}
-
-	defer func() {
-		if p := recover(); p != nil {
-			tx.Rollback()
-			panic(p)
-		} else if err != nil {
-			tx.Rollback()
-		}
-	}()
-
-	var txResultList []sql.Result
-
-	for _, query := range queryList {
-		stmt, prepareErr := tx.PrepareContext(ctx, query.Query)
-		if prepareErr != nil {
-			err = fmt.Errorf("prepare statement error: %w", prepareErr)
-			return nil, err
-		}
-
-		txResult, execErr := stmt.ExecContext(ctx, query.Params...)
-		stmt.Close()
-
-		if execErr != nil {
-			err = fmt.Errorf("exec prepared statement error: %w", execErr)
-			return nil, err
-		}
-
-		txResultList = append(txResultList, txResult)
-	}
-
-	if err = tx.Commit(); err != nil {
-		return nil, fmt.Errorf("commit transaction error: %w", err)
-	}
-
-	return txResultList, nil
+	return connect.ExecMultipleTx(context.Background(), nil, queryList)
 }
 
 // SqUpdateMultiple updates multiple records with transaction
 func (connect *DataBaseConnector) SqUpdateMultiple(queryList []PreparedQuery) ([]sql.Result, error) {
-	ctx := context.Background()
-
-	tx, err := connect.BeginTx(ctx, nil)
-	if err != nil {
-		return nil, fmt.Errorf("begin transaction error: %w", err)
-	}
-
-	defer func() {
-		if p := recover(); p != nil {
-			tx.Rollback()
-			panic(p)
-		} else if err != nil {
-			tx.Rollback()
-		}
-	}()
-
-	var txResultList []sql.Result
-
-	for _, query := range queryList {
-		stmt, prepareErr := tx.PrepareContext(ctx, query.Query)
-		if prepareErr != nil {
-			err = fmt.Errorf("prepare statement error: %w", prepareErr)
-			return nil, err
-		}
-
-		txResult, execErr := stmt.ExecContext(ctx, query.Params...)
-		stmt.Close()
-
-		if execErr != nil {
-			err = fmt.Errorf("exec prepared statement error: %w", execErr)
-			return nil, err
-		}
-
-		txResultList = append(txResultList, txResult)
-	}
-
-	if err = tx.Commit(); err != nil {
-		return nil, fmt.Errorf("commit transaction error: %w", err)
-	}
-
-	return txResultList, nil
+	return connect.ExecMultipleTx(context.Background(), nil, queryList)
 }
 
 // SqDeleteMultiple deletes multiple records with transaction
 func (connect *DataBaseConnector) SqDeleteMultiple(queryList []PreparedQuery) ([]sql.Result, error) {
-	ctx := context.Background()
-
-	tx, err := connect.BeginTx(ctx, nil)
-	if err != nil {
-		return nil, fmt.Errorf("begin transaction error: %w", err)
-	}
-
-	defer func() {
-		if p := recover(); p != nil {
-			tx.Rollback()
-			panic(p)
-		} else if err != nil {
-			tx.Rollback()
-		}
-	}()
-
-	var txResultList []sql.Result
-
-	for _, query := range queryList {
-		stmt, prepareErr := tx.PrepareContext(ctx, query.Query)
-		if prepareErr != nil {
-			err = fmt.Errorf("prepare statement error: %w", prepareErr)
-			return nil, err
-		}
-
-		txResult, execErr := stmt.ExecContext(ctx, query.Params...)
-		stmt.Close()
-
-		if execErr != nil {
-			err = fmt.Errorf("exec prepared statement error: %w", execErr)
-		}
-
-		txResultList = append(txResultList, txResult)
-	}
-
-	if err = tx.Commit(); err != nil {
-		return nil, fmt.Errorf("commit transaction error: %w", err)
-	}
-
-	return txResultList, nil
+	return connect.ExecMultipleTx(context.Background(), nil, queryList)
 }
 
 // SqEnableWAL enables Write-Ahead Logging for better concurrency.
@@ -292,6 +159,52 @@ func (connect *DataBaseConnector) SqGetVersion() (string, error) {
 	return version, nil
 }
 
+// sqliteReturningVersion is the first SQLite release supporting RETURNING.
+const sqliteReturningVersion = "3.35.0"
+
+// SqSupportsReturning reports whether the connected SQLite supports RETURNING clauses (3.35.0+).
+// Builders targeting Sqlite emit RETURNING, which older libraries reject with a syntax error.
+func (connect *DataBaseConnector) SqSupportsReturning() (bool, error) {
+	return connect.sqliteReturning.supports(connect)
+}
+
+// sqliteReturning caches the RETURNING support of a connector's SQLite library after the first successful check.
+type sqliteReturning struct {
+	mu        sync.Mutex
+	checked   bool
+	supported bool
+}
+
+func (r *sqliteReturning) supports(conn Querier) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.checked {
+		supported, err := sqliteSupportsReturning(conn)
+		if err != nil {
+			return false, err
+		}
+		r.supported, r.checked = supported, true
+	}
+	return r.supported, nil
+}
+
+// sqliteReturningSupport checks a connector's library once, and other queriers such as transactions on every call.
+func sqliteReturningSupport(conn Querier) (bool, error) {
+	if connect, ok := conn.(*DataBaseConnector); ok {
+		return connect.sqliteReturning.supports(connect)
+	}
+	return sqliteSupportsReturning(conn)
+}
+
+// sqliteSupportsReturning checks the SQLite library behind conn, which may be a connector or a transaction.
+func sqliteSupportsReturning(conn Querier) (bool, error) {
+	var version string
+	if err := conn.QueryRow("SELECT sqlite_version()").Scan(&version); err != nil {
+		return false, fmt.Errorf("failed to get SQLite version: %w", err)
+	}
+	return versionAtLeast(version, sqliteReturningVersion), nil
+}
+
 // SqVacuum performs VACUUM operation to reclaim space
 func (connect *DataBaseConnector) SqVacuum() error {
 	_, err := connect.Exec("VACUUM")
@@ -309,3 +222,97 @@ func (connect *DataBaseConnector) SqAnalyze() error {
 	}
 	return nil
 }
+
+// timeFormatConn binds time.Time arguments in format as text or Unix seconds that compare correctly.
+// The conversion runs in the driver, so queries, prepared statements and transactions all store the same format.
+type timeFormatConn struct {
+	driver.Conn
+	format string
+}
+
+// wrapTimeFormat returns the openDB connection wrapper for DBConfig.TimeFormat, nil when it is empty.
+func wrapTimeFormat(format string) func(driver.Conn) driver.Conn {
+	if format == "" {
+		return nil
+	}
+	return func(conn driver.Conn) driver.Conn {
+		return timeFormatConn{Conn: conn, format: format}
+	}
+}
+
+// CheckNamedValue converts time arguments and leaves every other argument to the driver.
+func (c timeFormatConn) CheckNamedValue(nv *driver.NamedValue) error {
+	var t time.Time
+	switch v := nv.Value.(type) {
+	case time.Time:
+		t = v
+	case *time.Time:
+		if v == nil {
+			return driver.ErrSkip
+		}
+		t = *v
+	default:
+		return driver.ErrSkip
+	}
+
+	if c.format == TimeFormatUnix {
+		nv.Value = t.Unix()
+	} else {
+		nv.Value = t.UTC().Format(c.format)
+	}
+	return nil
+}
+
+func (c timeFormatConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if preparer, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		return preparer.PrepareContext(ctx, query)
+	}
+	return c.Conn.Prepare(query)
+}
+
+func (c timeFormatConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if beginner, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return beginner.BeginTx(ctx, opts)
+	}
+	if opts.Isolation != driver.IsolationLevel(sql.LevelDefault) || opts.ReadOnly {
+		return nil, errors.New("sqlite driver does not support transaction options")
+	}
+	return c.Conn.Begin()
+}
+
+// ExecContext and QueryContext return driver.ErrSkip when the driver cannot run queries directly,
+// so database/sql prepares a statement instead.
+func (c timeFormatConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if execer, ok := c.Conn.(driver.ExecerContext); ok {
+		return execer.ExecContext(ctx, query, args)
+	}
+	return nil, driver.ErrSkip
+}
+
+func (c timeFormatConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if queryer, ok := c.Conn.(driver.QueryerContext); ok {
+		return queryer.QueryContext(ctx, query, args)
+	}
+	return nil, driver.ErrSkip
+}
+
+func (c timeFormatConn) Ping(ctx context.Context) error {
+	if pinger, ok := c.Conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+func (c timeFormatConn) ResetSession(ctx context.Context) error {
+	if resetter, ok := c.Conn.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c timeFormatConn) IsValid() bool {
+	if validator, ok := c.Conn.(driver.Validator); ok {
+		return validator.IsValid()
+	}
+	return true
+}
@@ -0,0 +1,186 @@
+package gdct
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFromSubquery(t *testing.T) {
+	sub := BuildSelect(PostgreSQL, "orders", "user_id", "amount").
+		Where("status = ?", "paid").
+		Where("amount > ?", 100)
+
+	query, args, err := BuildSelect(PostgreSQL, "big_orders", "user_id").
+		SelectRaw("SUM(amount) AS total").
+		FromSubquery(sub, "big_orders").
+		Where("user_id <> ?", 0).
+		GroupBy("user_id").
+		Limit(5).
+		Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "SELECT user_id, SUM(amount) AS total FROM (SELECT user_id, amount FROM orders WHERE status = $1 AND amount > $2) AS big_orders WHERE user_id <> $3 GROUP BY user_id LIMIT $4"
+	if query != expected {
+		t.Errorf("Expected %q, got %q", expected, query)
+	}
+	if len(args) != 4 || args[0] != "paid" || args[1] != 100 || args[2] != 0 || args[3] != int64(5) {
+		t.Errorf("Expected args [paid 100 0 5], got %v", args)
+	}
+}
+
+func TestFromSubqueryErrors(t *testing.T) {
+	_, _, err := BuildSelect(PostgreSQL, "t").FromSubquery(BuildSelect(PostgreSQL, "orders;"), "t").Build()
+	if !errors.Is(err, ErrInvalidIdentifier) {
+		t.Errorf("Expected sub-builder error to propagate, got %v", err)
+	}
+
+	_, _, err = BuildSelect(PostgreSQL, "t").FromSubquery(BuildDelete(PostgreSQL, "orders"), "t").Build()
+	if err == nil {
+		t.Errorf("Expected error for non-SELECT subquery")
+	}
+}
+
+func TestJoinSub(t *testing.T) {
+	totals := BuildSelect(PostgreSQL, "orders", "user_id").
+		SelectRaw("SUM(amount) AS total").
+		Where("status = ?", "paid").
+		GroupBy("user_id").
+		Having("SUM(amount) > ?", 1000)
+
+	query, args, err := BuildSelect(PostgreSQL, "users", "u.id", "t.total").
+		Alias("u").
+		SelectRaw("? AS tier", "gold").
+		JoinSub("inner", totals, "t", "t.user_id = u.id").
+		Where("u.active = ?", true).
+		Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "SELECT u.id, t.total, $1 AS tier FROM users AS u INNER JOIN (SELECT user_id, SUM(amount) AS total FROM orders WHERE status = $2 GROUP BY user_id HAVING SUM(amount) > $3) AS t ON t.user_id = u.id WHERE u.active = $4"
+	if query != expected {
+		t.Errorf("Expected %q, got %q", expected, query)
+	}
+	if len(args) != 4 || args[0] != "gold" || args[1] != "paid" || args[2] != 1000 || args[3] != true {
+		t.Errorf("Expected args [gold paid 1000 true], got %v", args)
+	}
+
+	_, _, err = BuildSelect(PostgreSQL, "users").JoinSub("sideways", totals, "t", "t.user_id = users.id").Build()
+	if err == nil {
+		t.Errorf("Expected error for unsupported join type")
+	}
+}
+
+func TestLateralJoin(t *testing.T) {
+	latest := BuildSelect(PostgreSQL, "posts", "p.title").
+		Alias("p").
+		Where("p.user_id = u.id").
+		Where("p.published = ?", true).
+		OrderBy("p.created_at", "DESC", nil).
+		Limit(3)
+
+	query, args, err := BuildSelect(PostgreSQL, "users", "u.name", "lp.title").
+		Alias("u").
+		LateralJoin("LEFT", latest, "lp", "true").
+		Where("u.active = ?", true).
+		Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "SELECT u.name, lp.title FROM users AS u LEFT JOIN LATERAL (SELECT p.title FROM posts AS p WHERE p.user_id = u.id AND p.published = $1 ORDER BY p.created_at DESC LIMIT $2) AS lp ON true WHERE u.active = $3"
+	if query != expected {
+		t.Errorf("Expected %q, got %q", expected, query)
+	}
+	if len(args) != 3 || args[0] != true || args[1] != int64(3) || args[2] != true {
+		t.Errorf("Expected args [true 3 true], got %v", args)
+	}
+
+	_, _, err = BuildSelect(Sqlite, "users").LateralJoin("LEFT", latest, "lp", "true").Build()
+	if !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Expected ErrUnsupported for SQLite, got %v", err)
+	}
+}
+
+func TestFromValues(t *testing.T) {
+	rows := [][]interface{}{{1, "gold"}, {2, "silver"}}
+
+	query, args, err := BuildSelect(PostgreSQL, "v", "v.id", "v.tier").
+		FromValues("v", []string{"id", "tier"}, rows).
+		Where("v.id > ?", 0).
+		Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "SELECT v.id, v.tier FROM (VALUES ($1, $2), ($3, $4)) AS v(id, tier) WHERE v.id > $5"
+	if query != expected {
+		t.Errorf("Expected %q, got %q", expected, query)
+	}
+	if len(args) != 5 || args[0] != 1 || args[1] != "gold" || args[2] != 2 || args[3] != "silver" || args[4] != 0 {
+		t.Errorf("Expected args [1 gold 2 silver 0], got %v", args)
+	}
+
+	query, _, err = BuildSelect(Mysql, "v", "v.id").FromValues("v", []string{"id", "tier"}, rows).Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected = "SELECT v.id FROM (SELECT ? AS id, ? AS tier UNION ALL SELECT ?, ?) AS v"
+	if query != expected {
+		t.Errorf("Expected %q, got %q", expected, query)
+	}
+
+	_, _, err = BuildSelect(PostgreSQL, "v").FromValues("v", []string{"id", "tier"}, [][]interface{}{{1}}).Build()
+	if err == nil {
+		t.Errorf("Expected error for row with missing values")
+	}
+}
+
+func TestFromValuesSqlite(t *testing.T) {
+	conn := openTestSqlite(t, DBConfig{})
+	createTestUsers(t, conn)
+	if _, err := conn.Exec("INSERT INTO users (name, age) VALUES ('John', 30), ('Jane', 25)"); err != nil {
+		t.Fatalf("Insert failed: %v", err)
+	}
+
+	qb := BuildSelect(Sqlite, "users", "users.name").
+		JoinSub("INNER", BuildSelect(Sqlite, "lookup", "name").
+			FromValues("lookup", []string{"name"}, [][]interface{}{{"Jane"}, {"Nobody"}}), "l", "l.name = users.name")
+
+	names, err := SelectAll[string](conn, qb)
+	if err != nil {
+		t.Fatalf("SelectAll failed: %v", err)
+	}
+	if len(names) != 1 || names[0] != "Jane" {
+		t.Errorf("Expected [Jane], got %v", names)
+	}
+}
+
+func TestSelectSubquery(t *testing.T) {
+	postCount := BuildCountSelect(PostgreSQL, "posts", "*").
+		Where("posts.user_id = users.id").
+		Where("posts.status = ?", "published")
+
+	query, args, err := BuildSelect(PostgreSQL, "users", "id", "name").
+		SelectSubquery(postCount, "post_count").
+		Where("users.active = ?", true).
+		Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "SELECT id, name, (SELECT COUNT(*) FROM posts WHERE posts.user_id = users.id AND posts.status = $1) AS post_count FROM users WHERE users.active = $2"
+	if query != expected {
+		t.Errorf("Expected %q, got %q", expected, query)
+	}
+	if len(args) != 2 || args[0] != "published" || args[1] != true {
+		t.Errorf("Expected args [published true], got %v", args)
+	}
+
+	_, _, err = BuildSelect(PostgreSQL, "users").SelectSubquery(BuildDelete(PostgreSQL, "posts"), "n").Build()
+	if err == nil {
+		t.Errorf("Expected error for non-SELECT subquery")
+	}
+}
@@ -0,0 +1,143 @@
+package gdct
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+)
+
+/*
+beginTx
+
+@ ctx: Context for the transaction
+@ opts: Transaction options such as isolation level and read-only mode (nil for defaults)
+@ Return: Started transaction, release function to call once the transaction is done, and error if any
+
+SQLite ignores TxOptions.ReadOnly, so read-only transactions are enforced
+by pinning a connection with PRAGMA query_only for the transaction's lifetime.
+*/
+func (connect *DataBaseConnector) beginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, func(), error) {
+	if connect.dbType != Sqlite || opts == nil || !opts.ReadOnly {
+		tx, err := connect.BeginTx(ctx, opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		return tx, func() {}, nil
+	}
+
+	conn, err := connect.Conn(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if _, err := conn.ExecContext(ctx, "PRAGMA query_only = ON"); err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+
+	release := func() {
+		if _, err := conn.ExecContext(context.Background(), "PRAGMA query_only = OFF"); err != nil {
+			log.Printf("[TRANSACTION] Reset query_only error: %v", err)
+		}
+		conn.Close()
+	}
+
+	tx, err := conn.BeginTx(ctx, opts)
+	if err != nil {
+		release()
+		return nil, nil, err
+	}
+
+	return tx, release, nil
+}
+
+/*
+WithTransaction
+
+@ ctx: Context for the transaction
+@ opts: Transaction options such as isolation level and read-only mode (nil for defaults)
+@ fn: Function executed within the transaction
+@ Return: Error if any
+
+The transaction is committed when fn returns nil and rolled back otherwise.
+*/
+func (connect *DataBaseConnector) WithTransaction(ctx context.Context, opts *sql.TxOptions, fn func(*sql.Tx) error) (err error) {
+	if err := connect.inFlight.begin(); err != nil {
+		return err
+	}
+	defer connect.inFlight.end()
+
+	tx, release, txErr := connect.beginTx(ctx, opts)
+	if txErr != nil {
+		return fmt.Errorf("begin transaction error: %w", txErr)
+	}
+	defer release()
+
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if fnErr := fn(tx); fnErr != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil && rollbackErr != sql.ErrTxDone {
+			log.Printf("[TRANSACTION] Transaction rollback error: %v", rollbackErr)
+		}
+		return fnErr
+	}
+
+	if commitErr := tx.Commit(); commitErr != nil {
+		return fmt.Errorf("commit transaction error: %w", commitErr)
+	}
+
+	return nil
+}
+
+/*
+ExecMultipleTx
+
+@ ctx: Context for the transaction
+@ opts: Transaction options such as isolation level and read-only mode (nil for defaults)
+@ queryList: Prepared queries executed in order
+@ Return: Results of each query and error if any
+*/
+func (connect *DataBaseConnector) ExecMultipleTx(ctx context.Context, opts *sql.TxOptions, queryList []PreparedQuery) ([]sql.Result, error) {
+	var txResultList []sql.Result
+
+	err := connect.WithTransaction(ctx, opts, func(tx *sql.Tx) error {
+		// Queries sharing the same SQL reuse one prepared statement
+		stmts := make(map[string]*sql.Stmt)
+		defer func() {
+			for _, stmt := range stmts {
+				stmt.Close()
+			}
+		}()
+
+		for _, query := range queryList {
+			stmt, ok := stmts[query.Query]
+			if !ok {
+				var prepareErr error
+				stmt, prepareErr = tx.PrepareContext(ctx, query.Query)
+				if prepareErr != nil {
+					return fmt.Errorf("prepare statement error: %w", prepareErr)
+				}
+				stmts[query.Query] = stmt
+			}
+
+			txResult, execErr := stmt.ExecContext(ctx, query.Params...)
+			if execErr != nil {
+				return fmt.Errorf("exec prepared statement error: %w", execErr)
+			}
+
+			txResultList = append(txResultList, txResult)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return txResultList, nil
+}
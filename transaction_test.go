@@ -0,0 +1,96 @@
+package gdct
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadOnlyTransaction(t *testing.T) {
+	openConns := 1
+	conn := openTestSqlite(t, DBConfig{MaxOpenConns: &openConns})
+	createTestUsers(t, conn)
+
+	ctx := context.Background()
+	readOnly := &sql.TxOptions{ReadOnly: true}
+
+	err := conn.WithTransaction(ctx, readOnly, func(tx *sql.Tx) error {
+		var count int
+		if err := tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM users").Scan(&count); err != nil {
+			t.Errorf("Read inside read-only transaction failed: %v", err)
+		}
+		_, err := tx.ExecContext(ctx, "INSERT INTO users (name) VALUES ('John')")
+		return err
+	})
+	if err == nil {
+		t.Fatalf("Expected write inside read-only transaction to fail")
+	}
+
+	_, err = conn.ExecMultipleTx(ctx, readOnly, []PreparedQuery{
+		{Query: "INSERT INTO users (name) VALUES (?)", Params: []interface{}{"John"}},
+	})
+	if err == nil {
+		t.Fatalf("Expected ExecMultipleTx write inside read-only transaction to fail")
+	}
+
+	results, err := conn.ExecMultipleTx(ctx, nil, []PreparedQuery{
+		{Query: "INSERT INTO users (name) VALUES (?)", Params: []interface{}{"John"}},
+		{Query: "INSERT INTO users (name) VALUES (?)", Params: []interface{}{"Jane"}},
+	})
+	if err != nil {
+		t.Fatalf("Write after read-only transaction failed: %v", err)
+	}
+	if len(results) != 2 {
+		t.Errorf("Expected 2 results, got %d", len(results))
+	}
+}
+
+func TestExecMultipleTxResultOrder(t *testing.T) {
+	conn := openTestSqlite(t, DBConfig{})
+	createTestUsers(t, conn)
+
+	insert := "INSERT INTO users (name) VALUES (?)"
+	results, err := conn.ExecMultipleTx(context.Background(), nil, []PreparedQuery{
+		{Query: insert, Params: []interface{}{"John"}},
+		{Query: "UPDATE users SET age = ? WHERE name = ?", Params: []interface{}{40, "John"}},
+		{Query: insert, Params: []interface{}{"Jane"}},
+	})
+	if err != nil {
+		t.Fatalf("ExecMultipleTx failed: %v", err)
+	}
+
+	for i, expected := range []int64{1, 1, 2} {
+		id, err := results[i].LastInsertId()
+		if err != nil {
+			t.Fatalf("LastInsertId failed: %v", err)
+		}
+		if id != expected {
+			t.Errorf("Result %d: expected last insert id %d, got %d", i, expected, id)
+		}
+	}
+}
+
+func BenchmarkExecMultipleTxSameQuery(b *testing.B) {
+	conn, err := InitConnection(Sqlite, DBConfig{Database: filepath.Join(b.TempDir(), "bench.sqlite")})
+	if err != nil {
+		b.Fatalf("Create connection error: %v", err)
+	}
+	defer conn.Close()
+
+	if err := conn.SqCreateTable([]string{"CREATE TABLE users (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT NOT NULL)"}); err != nil {
+		b.Fatalf("Create table failed: %v", err)
+	}
+
+	queryList := make([]PreparedQuery, 1000)
+	for i := range queryList {
+		queryList[i] = PreparedQuery{Query: "INSERT INTO users (name) VALUES (?)", Params: []interface{}{"John"}}
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := conn.ExecMultipleTx(context.Background(), nil, queryList); err != nil {
+			b.Fatalf("ExecMultipleTx failed: %v", err)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package gdct
+
+import (
+	"fmt"
+	"strings"
+)
+
+// conflictClause is the ON CONFLICT part of a PostgreSQL or SQLite upsert.
+type conflictClause struct {
+	target  string     // "(col, ...)" or "ON CONSTRAINT name"
+	where   *sqlClause // Partial index predicate of the conflict target
+	updates []string   // Escaped columns updated from EXCLUDED, DO NOTHING when empty
+}
+
+/*
+OnConflict
+
+@ columns: Conflict target columns
+@ updates: Columns updated with the excluded row values, DO NOTHING when empty
+@ Return: *QueryBuilder with ON CONFLICT (columns) clause set
+*/
+func (qb *QueryBuilder) OnConflict(columns []string, updates ...string) *QueryBuilder {
+	if !qb.checkUpsert("OnConflict", FeatureOnConflict) {
+		return qb
+	}
+	if len(columns) == 0 {
+		qb.err = fmt.Errorf("OnConflict() requires at least one conflict column")
+		return qb
+	}
+	safeColumns := sanitizeColumns(qb.dialect, columns, &qb.err)
+	if qb.err != nil {
+		return qb
+	}
+	qb.setConflict("("+strings.Join(safeColumns, ", ")+")", updates)
+	return qb
+}
+
+/*
+OnConflictConstraint
+
+@ name: Constraint name used as the conflict target
+@ updates: Columns updated with the excluded row values, DO NOTHING when empty
+@ Return: *QueryBuilder with ON CONFLICT ON CONSTRAINT clause set
+
+Only PostgreSQL is supported.
+*/
+func (qb *QueryBuilder) OnConflictConstraint(name string, updates ...string) *QueryBuilder {
+	if !qb.checkUpsert("OnConflictConstraint", FeatureConflictConstraint) {
+		return qb
+	}
+	safeName, err := qb.dialect.EscapeIdentifier(name)
+	if err != nil {
+		qb.err = fmt.Errorf("invalid constraint name: %w", err)
+		return qb
+	}
+	qb.setConflict("ON CONSTRAINT "+safeName, updates)
+	return qb
+}
+
+/*
+OnConflictWhere
+
+@ predicate: Partial index predicate of the conflict target
+@ args: Arguments for the predicate placeholders
+@ Return: *QueryBuilder with ON CONFLICT (columns) WHERE predicate set
+*/
+func (qb *QueryBuilder) OnConflictWhere(predicate string, args ...interface{}) *QueryBuilder {
+	if qb.err != nil {
+		return qb
+	}
+	if qb.conflict == nil || !strings.HasPrefix(qb.conflict.target, "(") {
+		qb.err = fmt.Errorf("OnConflictWhere() requires OnConflict() with conflict columns")
+		return qb
+	}
+	if predicate == "" {
+		qb.err = fmt.Errorf("condition cannot be empty")
+		return qb
+	}
+	qb.conflict.where = &sqlClause{sql: predicate, args: args}
+	return qb
+}
+
+/*
+InsertIgnore
+
+@ Return: *QueryBuilder skipping rows that conflict with existing ones
+
+Builds INSERT IGNORE for MariaDB and Mysql and ON CONFLICT DO NOTHING for PostgreSQL and Sqlite.
+An explicit OnConflict() clause takes precedence. Other databases are not supported.
+*/
+func (qb *QueryBuilder) InsertIgnore() *QueryBuilder {
+	if qb.err != nil {
+		return qb
+	}
+	if qb.op != "INSERT" {
+		qb.err = fmt.Errorf("InsertIgnore() can only be used with INSERT operation")
+		return qb
+	}
+	if !qb.insertIgnoreVerb() && !qb.dbType.Supports(FeatureOnConflict) {
+		qb.err = fmt.Errorf("InsertIgnore() %w: %s", ErrUnsupported, qb.dbType)
+		return qb
+	}
+	qb.ignore = true
+	return qb
+}
+
+/*
+Replace
+
+@ Return: *QueryBuilder building REPLACE INTO instead of INSERT INTO
+
+Only MariaDB and Mysql are supported, use OnConflict() for PostgreSQL and Sqlite.
+*/
+func (qb *QueryBuilder) Replace() *QueryBuilder {
+	if qb.err != nil {
+		return qb
+	}
+	if qb.op != "INSERT" {
+		qb.err = fmt.Errorf("Replace() can only be used with INSERT operation")
+		return qb
+	}
+	if !qb.dbType.Supports(FeatureReplace) {
+		qb.err = fmt.Errorf("Replace() %w: %s, use OnConflict() to upsert instead", ErrUnsupported, qb.dbType)
+		return qb
+	}
+	qb.replace = true
+	return qb
+}
+
+// insertVerb returns the statement keyword of an INSERT query.
+func (qb *QueryBuilder) insertVerb() string {
+	if qb.replace {
+		return "REPLACE INTO"
+	}
+	if qb.ignore && qb.insertIgnoreVerb() {
+		return "INSERT IGNORE INTO"
+	}
+	return "INSERT INTO"
+}
+
+// insertIgnoreVerb reports whether the database skips conflicting rows with INSERT IGNORE.
+func (qb *QueryBuilder) insertIgnoreVerb() bool {
+	return qb.dbType == MariaDB || qb.dbType == Mysql
+}
+
+func (qb *QueryBuilder) checkUpsert(method string, feature Feature) bool {
+	if qb.err != nil {
+		return false
+	}
+	if qb.op != "INSERT" {
+		qb.err = fmt.Errorf("%s() can only be used with INSERT operation", method)
+		return false
+	}
+	if !qb.dbType.Supports(feature) {
+		qb.err = fmt.Errorf("%s() %w: %s", method, ErrUnsupported, qb.dbType)
+		return false
+	}
+	return true
+}
+
+func (qb *QueryBuilder) setConflict(target string, updates []string) {
+	safeUpdates := make([]string, 0, len(updates))
+	for _, col := range updates {
+		safeCol, err := qb.dialect.EscapeIdentifier(col)
+		if err != nil {
+			qb.err = err
+			return
+		}
+		safeUpdates = append(safeUpdates, safeCol)
+	}
+	qb.conflict = &conflictClause{target: target, updates: safeUpdates}
+}
+
+// writeConflict renders the ON CONFLICT clause of an INSERT.
+func (w *queryWriter) writeConflict(c *conflictClause) {
+	w.WriteString(" ON CONFLICT " + c.target)
+	if c.where != nil {
+		w.WriteString(" WHERE ")
+		w.writeClause(*c.where)
+	}
+	if len(c.updates) == 0 {
+		w.WriteString(" DO NOTHING")
+		return
+	}
+	assignments := make([]string, len(c.updates))
+	for i, col := range c.updates {
+		assignments[i] = col + " = EXCLUDED." + col
+	}
+	w.WriteString(" DO UPDATE SET " + strings.Join(assignments, ", "))
+}
@@ -0,0 +1,168 @@
+package gdct
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestOnConflict(t *testing.T) {
+	tests := []struct {
+		name          string
+		qb            *QueryBuilder
+		expectedQuery string
+		expectedArgs  int
+	}{
+		{
+			name: "Columns with update",
+			qb: BuildInsert(PostgreSQL, "users").
+				Values(map[string]interface{}{"email": "a@b.c", "name": "John"}).
+				OnConflict([]string{"email"}, "name"),
+			expectedQuery: "INSERT INTO users (email, name) VALUES ($1, $2) ON CONFLICT (email) DO UPDATE SET name = EXCLUDED.name",
+			expectedArgs:  2,
+		},
+		{
+			name: "Partial index predicate",
+			qb: BuildInsert(PostgreSQL, "users").
+				Values(map[string]interface{}{"email": "a@b.c", "name": "John"}).
+				OnConflict([]string{"email"}, "name").
+				OnConflictWhere("deleted_at IS NULL AND tenant_id = ?", 3),
+			expectedQuery: "INSERT INTO users (email, name) VALUES ($1, $2) ON CONFLICT (email) WHERE deleted_at IS NULL AND tenant_id = $3 DO UPDATE SET name = EXCLUDED.name",
+			expectedArgs:  3,
+		},
+		{
+			name: "Constraint name",
+			qb: BuildInsert(PostgreSQL, "users").
+				Values(map[string]interface{}{"email": "a@b.c", "name": "John"}).
+				OnConflictConstraint("users_email_key", "name", "email"),
+			expectedQuery: "INSERT INTO users (email, name) VALUES ($1, $2) ON CONFLICT ON CONSTRAINT users_email_key DO UPDATE SET name = EXCLUDED.name, email = EXCLUDED.email",
+			expectedArgs:  2,
+		},
+		{
+			name: "Do nothing",
+			qb: BuildInsert(Sqlite, "users").
+				Values(map[string]interface{}{"email": "a@b.c"}).
+				OnConflict([]string{"email"}),
+			expectedQuery: "INSERT INTO users (email) VALUES (?) ON CONFLICT (email) DO NOTHING",
+			expectedArgs:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args, err := tt.qb.Build()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if query != tt.expectedQuery {
+				t.Errorf("Expected %q, got %q", tt.expectedQuery, query)
+			}
+			if len(args) != tt.expectedArgs {
+				t.Errorf("Expected %d args, got %d", tt.expectedArgs, len(args))
+			}
+		})
+	}
+}
+
+func TestOnConflictErrors(t *testing.T) {
+	_, _, err := BuildInsert(Mysql, "users").
+		Values(map[string]interface{}{"email": "a@b.c"}).
+		OnConflict([]string{"email"}).
+		Build()
+	if !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Expected ErrUnsupported for MySQL, got %v", err)
+	}
+
+	_, _, err = BuildInsert(Sqlite, "users").
+		Values(map[string]interface{}{"email": "a@b.c"}).
+		OnConflictConstraint("users_email_key").
+		Build()
+	if !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Expected ErrUnsupported for SQLite constraint target, got %v", err)
+	}
+
+	_, _, err = BuildInsert(PostgreSQL, "users").
+		Values(map[string]interface{}{"email": "a@b.c"}).
+		OnConflictConstraint("users_email_key").
+		OnConflictWhere("deleted_at IS NULL").
+		Build()
+	if err == nil {
+		t.Errorf("Expected error for OnConflictWhere with constraint target")
+	}
+}
+
+func TestInsertIgnore(t *testing.T) {
+	data := map[string]interface{}{"email": "a@b.c", "name": "John"}
+	tests := []struct {
+		dbType        DBType
+		expectedQuery string
+	}{
+		{PostgreSQL, "INSERT INTO users (email, name) VALUES ($1, $2) ON CONFLICT DO NOTHING"},
+		{Sqlite, "INSERT INTO users (email, name) VALUES (?, ?) ON CONFLICT DO NOTHING"},
+		{Mysql, "INSERT IGNORE INTO users (email, name) VALUES (?, ?)"},
+		{MariaDB, "INSERT IGNORE INTO users (email, name) VALUES (?, ?)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dbType.String(), func(t *testing.T) {
+			query, args, err := BuildInsert(tt.dbType, "users").Values(data).InsertIgnore().Build()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if query != tt.expectedQuery {
+				t.Errorf("Expected %q, got %q", tt.expectedQuery, query)
+			}
+			if len(args) != 2 {
+				t.Errorf("Expected 2 args, got %d", len(args))
+			}
+		})
+	}
+
+	query, _, err := BuildInsert(Mysql, "users").
+		ValuesRows([]string{"email"}, [][]interface{}{{"a@b.c"}, {"d@e.f"}}).
+		InsertIgnore().
+		Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if expected := "INSERT IGNORE INTO users (email) VALUES (?), (?)"; query != expected {
+		t.Errorf("Expected %q, got %q", expected, query)
+	}
+
+	_, _, err = BuildUpdate(Mysql, "users").Set(data).InsertIgnore().Build()
+	if err == nil {
+		t.Errorf("Expected error for InsertIgnore on UPDATE")
+	}
+
+	_, _, err = BuildInsert(SQLServer, "users").Values(data).InsertIgnore().Build()
+	if !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Expected ErrUnsupported for InsertIgnore on SQLServer, got %v", err)
+	}
+}
+
+func TestReplace(t *testing.T) {
+	query, args, err := BuildInsert(Mysql, "users").
+		Values(map[string]interface{}{"id": 1, "name": "John"}).
+		Replace().
+		Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "REPLACE INTO users (id, name) VALUES (?, ?)"
+	if query != expected {
+		t.Errorf("Expected %q, got %q", expected, query)
+	}
+	if len(args) != 2 {
+		t.Errorf("Expected 2 args, got %d", len(args))
+	}
+
+	for _, dbType := range []DBType{PostgreSQL, Sqlite} {
+		_, _, err := BuildInsert(dbType, "users").
+			Values(map[string]interface{}{"id": 1}).
+			Replace().
+			Build()
+		if !errors.Is(err, ErrUnsupported) {
+			t.Errorf("Expected ErrUnsupported for %s, got %v", dbType, err)
+		}
+	}
+}
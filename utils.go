@@ -1,6 +1,26 @@
 package gdct
 
-import "time"
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultPingTimeout bounds connection checks that are not given a context.
+const defaultPingTimeout = 10 * time.Second
+
+// pinger is implemented by *sql.DB and *DataBaseConnector.
+type pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// pingWithTimeout pings the database, giving up after defaultPingTimeout.
+func pingWithTimeout(connect pinger) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultPingTimeout)
+	defer cancel()
+	return connect.PingContext(ctx)
+}
 
 /*
 Default Values
@@ -8,6 +28,8 @@ Default Values
 Max Life Time: 60
 Max Idle Connections: 50
 Max Open Connections: 100
+SSL Mode (PostgreSQL): require
+Charset (MariaDB/MySQL): utf8mb4
 */
 func decideDefaultConfigs(cfg DBConfig, dbType DBType) DBConfig {
 	if cfg.MaxLifeTime == nil {
@@ -25,6 +47,10 @@ func decideDefaultConfigs(cfg DBConfig, dbType DBType) DBConfig {
 		cfg.MaxOpenConns = &defaultOpenConns
 	}
 
+	if (dbType == MariaDB || dbType == Mysql) && cfg.Charset == "" {
+		cfg.Charset = "utf8mb4"
+	}
+
 	if dbType == PostgreSQL && cfg.SslMode == nil {
 		defaultSslMode := "require"
 		cfg.SslMode = &defaultSslMode
@@ -33,11 +59,41 @@ func decideDefaultConfigs(cfg DBConfig, dbType DBType) DBConfig {
 	return cfg
 }
 
-// Convert String Slice into Interface Slice
-func convertArgs(args []string) []interface{} {
-	arguments := make([]interface{}, len(args))
-	for i, arg := range args {
-		arguments[i] = arg
+// versionAtLeast reports whether a dotted version string such as "3.45.1" is at least minimum.
+// Non-numeric suffixes of a part ("8.0.36-log") are ignored.
+func versionAtLeast(version, minimum string) bool {
+	have := strings.Split(version, ".")
+	want := strings.Split(minimum, ".")
+	for i, part := range want {
+		wantNum, _ := strconv.Atoi(part)
+		haveNum := 0
+		if i < len(have) {
+			haveNum = leadingInt(have[i])
+		}
+		if haveNum != wantNum {
+			return haveNum > wantNum
+		}
+	}
+	return true
+}
+
+func leadingInt(s string) int {
+	end := 0
+	for end < len(s) && s[end] >= '0' && s[end] <= '9' {
+		end++
+	}
+	n, _ := strconv.Atoi(s[:end])
+	return n
+}
+
+// maxSnippetLength bounds the query text quoted in error messages.
+const maxSnippetLength = 60
+
+// querySnippet collapses the whitespace of a query and shortens it for error messages.
+func querySnippet(query string) string {
+	snippet := strings.Join(strings.Fields(query), " ")
+	if runes := []rune(snippet); len(runes) > maxSnippetLength {
+		return string(runes[:maxSnippetLength]) + "..."
 	}
-	return arguments
+	return snippet
 }
@@ -0,0 +1,63 @@
+package gdct
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+)
+
+// NewUUID returns a random version 4 UUID in its canonical 36 character form.
+// It panics if the system random source fails, which crypto/rand treats as unrecoverable.
+func NewUUID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(fmt.Errorf("generate uuid error: %w", err))
+	}
+	b[6] = (b[6] & 0x0f) | 0x40 // Version 4
+	b[8] = (b[8] & 0x3f) | 0x80 // RFC 4122 variant
+
+	var buf [36]byte
+	hex.Encode(buf[0:8], b[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], b[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], b[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], b[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], b[10:])
+	return string(buf[:])
+}
+
+/*
+InsertWithUUID
+
+@ idColumn: Column receiving a generated UUID
+@ Return: *QueryBuilder with the UUID added to the INSERT data
+
+Must be called after Values(). A value already set for idColumn is kept, and the map given to Values() is not modified.
+*/
+func (qb *QueryBuilder) InsertWithUUID(idColumn string) *QueryBuilder {
+	if qb.err != nil {
+		return qb
+	}
+	if qb.op != "INSERT" || qb.data == nil {
+		qb.err = fmt.Errorf("InsertWithUUID() requires an INSERT with Values()")
+		return qb
+	}
+	if _, err := qb.dialect.EscapeIdentifier(idColumn); err != nil {
+		qb.err = err
+		return qb
+	}
+	if _, ok := qb.data[idColumn]; ok {
+		return qb
+	}
+
+	data := make(map[string]interface{}, len(qb.data)+1)
+	for col, val := range qb.data {
+		data[col] = val
+	}
+	data[idColumn] = NewUUID()
+	qb.data = data
+	return qb
+}
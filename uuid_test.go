@@ -0,0 +1,57 @@
+package gdct
+
+import (
+	"regexp"
+	"testing"
+)
+
+var uuidV4Regexp = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func TestNewUUID(t *testing.T) {
+	seen := make(map[string]bool)
+	for i := 0; i < 100; i++ {
+		id := NewUUID()
+		if !uuidV4Regexp.MatchString(id) {
+			t.Fatalf("Expected a v4 UUID, got %q", id)
+		}
+		if seen[id] {
+			t.Fatalf("Duplicate UUID %q", id)
+		}
+		seen[id] = true
+	}
+}
+
+func TestInsertWithUUID(t *testing.T) {
+	data := map[string]interface{}{"name": "John"}
+	query, args, err := BuildInsert(PostgreSQL, "users").Values(data).InsertWithUUID("id").Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "INSERT INTO users (id, name) VALUES ($1, $2)"
+	if query != expected {
+		t.Errorf("Expected %q, got %q", expected, query)
+	}
+	if id, ok := args[0].(string); !ok || !uuidV4Regexp.MatchString(id) {
+		t.Errorf("Expected a generated UUID, got %v", args[0])
+	}
+	if _, ok := data["id"]; ok {
+		t.Errorf("Expected the caller's map to be left unchanged")
+	}
+
+	_, args, err = BuildInsert(PostgreSQL, "users").
+		Values(map[string]interface{}{"id": "preset", "name": "John"}).
+		InsertWithUUID("id").
+		Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if args[0] != "preset" {
+		t.Errorf("Expected preset id to be kept, got %v", args[0])
+	}
+
+	_, _, err = BuildInsert(PostgreSQL, "users").InsertWithUUID("id").Build()
+	if err == nil {
+		t.Errorf("Expected error when Values() is missing")
+	}
+}